@@ -25,11 +25,11 @@ func main() {
 	// 在后台goroutine中监听信号
 	go func() {
 		sig := <-signalChan
-		fmt.Printf("\n接收到信号: %s，程序退出...\n\n", sig)
+		fmt.Fprintf(os.Stderr, "\n接收到信号: %s，程序退出...\n\n", sig)
 		cancel() // 取消context
 	}()
 
 	if err := cmd.Execute(ctx); err != nil {
-		os.Exit(1)
+		os.Exit(cmd.ExitCode(err))
 	}
 }
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/zboyco/s3ctl/internal/s3client"
+	"github.com/zboyco/s3ctl/internal/utils"
+)
+
+var catCmd = &cobra.Command{
+	Use:   "cat s3://bucket/object [s3://bucket/object...]",
+	Short: "将 S3 对象内容输出到标准输出",
+	Long: `按顺序读取一个或多个对象并写入标准输出，不输出任何进度信息，便于在管道中使用。
+
+示例:
+  s3ctl cat s3://backups/db.gz | gunzip | psql
+  s3ctl cat s3://logs/part-1.log s3://logs/part-2.log > all.log`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// 创建 S3 客户端
+		client, err := s3client.NewClient(cmd.Context(), false)
+		if err != nil {
+			return err
+		}
+
+		for _, s3Path := range args {
+			// 解析 S3 路径
+			bucketName, objectPath, err := utils.ParseS3Path(s3Path)
+			if err != nil {
+				return err
+			}
+
+			if err := client.CatObject(bucketName, objectPath, os.Stdout); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/zboyco/s3ctl/internal/s3client"
+	"github.com/zboyco/s3ctl/internal/utils"
+)
+
+var (
+	dryRun    bool   // 全局的 --dry-run 选项，只列出将要执行的操作而不实际执行
+	limitRate string // 全局的 --limit-rate 选项，为空时使用配置中的 limit_rate
+	progress  string // 全局的 --progress 选项，进度显示方式
+
+	// 全局的 --retries 和 --retry-max-wait 选项，未指定时使用配置中的值
+	retries      int
+	retryMaxWait time.Duration
+)
+
+// newClient 使用指定名称的配置创建 S3 客户端并应用全局选项，名称为空时使用当前配置
+func newClient(ctx context.Context, profile string, v2 bool) (*s3client.Client, error) {
+	client, err := s3client.NewClientWithProfile(ctx, profile, v2)
+	if err != nil {
+		return nil, err
+	}
+	client.SetDryRun(dryRun)
+
+	mode, err := s3client.ParseProgressMode(progress)
+	if err != nil {
+		return nil, err
+	}
+	client.SetProgressMode(mode)
+
+	if limitRate != "" {
+		rate, err := utils.ParseRate(limitRate)
+		if err != nil {
+			return nil, fmt.Errorf("解析限速失败: %w", err)
+		}
+		client.SetRateLimit(rate)
+	}
+
+	flags := rootCmd.PersistentFlags()
+	policy := client.RetryPolicy()
+	if flags.Changed("retries") {
+		if retries < 0 {
+			return nil, fmt.Errorf("重试次数不能小于 0")
+		}
+		policy.Retries = retries
+	}
+	if flags.Changed("retry-max-wait") {
+		if retryMaxWait <= 0 {
+			return nil, fmt.Errorf("重试最长等待时间必须大于 0")
+		}
+		policy.MaxWait = retryMaxWait
+	}
+	client.SetRetryPolicy(policy)
+	return client, nil
+}
+
+// printSuccess 输出操作成功的提示，演练模式下没有执行任何操作，不输出
+func printSuccess(msg string) {
+	if dryRun {
+		return
+	}
+	fmt.Println(msg)
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zboyco/s3ctl/internal/config"
+	"github.com/zboyco/s3ctl/internal/s3client"
+	"github.com/zboyco/s3ctl/internal/utils"
+)
+
+var (
+	cpContentType string
+	cpMetadata    []string
+)
+
+var cpCmd = &cobra.Command{
+	Use:   "cp [profile:]s3://bucket/source [profile:]s3://bucket/target",
+	Short: "在服务端复制 S3 对象",
+	Long: `在服务端复制对象，数据不经过本地。超过 5GiB 的对象会自动使用分片复制。
+源路径以 / 结尾时递归复制整个前缀。默认保留源对象的用户元数据和 Content-Type，
+可使用 --content-type 和 --metadata 覆盖。
+
+示例:
+  s3ctl cp s3://src-bucket/data/file.bin s3://dst-bucket/backup/file.bin
+  s3ctl cp s3://src-bucket/data/ s3://dst-bucket/backup/
+  s3ctl cp s3://mybucket/a.json s3://mybucket/b.json --content-type application/json --metadata owner=ops
+
+路径前可以加上配置名，在不同的服务之间传输，数据直接从源服务流式写入目标服务，不落地到本地磁盘:
+  s3ctl cp minio:s3://bucket/data/ oss:s3://bucket/data/`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		metadata, err := utils.ParseKeyValues(cpMetadata)
+		if err != nil {
+			return err
+		}
+		copyOpts := s3client.CopyOptions{
+			ContentType: cpContentType,
+			Metadata:    metadata,
+		}
+
+		return copyS3Path(cmd.Context(), args[0], args[1], copyOpts)
+	},
+}
+
+func init() {
+	cpCmd.Flags().StringVar(&cpContentType, "content-type", "", "覆盖目标对象的 Content-Type")
+	cpCmd.Flags().StringArrayVar(&cpMetadata, "metadata", nil, "覆盖或追加用户元数据，格式为 key=value，可重复指定")
+}
+
+// copyS3Path 复制 S3 路径，两端为同一配置时在服务端复制，否则在两个服务之间流式传输
+func copyS3Path(ctx context.Context, srcPath, dstPath string, copyOpts s3client.CopyOptions) error {
+	// 解析源路径和目标路径
+	srcProfile, srcBucket, srcObject, err := utils.ParseProfileS3Path(srcPath)
+	if err != nil {
+		return err
+	}
+	dstProfile, dstBucket, dstObject, err := utils.ParseProfileS3Path(dstPath)
+	if err != nil {
+		return err
+	}
+
+	same, err := sameProfile(srcProfile, dstProfile)
+	if err != nil {
+		return err
+	}
+
+	// 创建 S3 客户端
+	srcClient, err := newClient(ctx, srcProfile, false)
+	if err != nil {
+		return err
+	}
+	dstClient := srcClient
+	if !same {
+		if dstClient, err = newClient(ctx, dstProfile, false); err != nil {
+			return err
+		}
+	}
+
+	// 源路径以 / 结尾时递归复制前缀
+	if srcObject == "" || strings.HasSuffix(srcObject, "/") {
+		if same {
+			err = srcClient.CopyDirectory(srcBucket, srcObject, dstBucket, dstObject, copyOpts)
+		} else {
+			err = srcClient.TransferDirectory(dstClient, srcBucket, srcObject, dstBucket, dstObject, copyOpts)
+		}
+		if err != nil {
+			return err
+		}
+		printSuccess("目录复制成功")
+		return nil
+	}
+
+	// 目标为前缀时使用源对象的文件名
+	if dstObject == "" || strings.HasSuffix(dstObject, "/") {
+		dstObject += path.Base(srcObject)
+	}
+
+	if same {
+		err = srcClient.CopyObject(srcBucket, srcObject, dstBucket, dstObject, copyOpts)
+	} else {
+		err = srcClient.TransferObject(dstClient, srcBucket, srcObject, dstBucket, dstObject, copyOpts)
+	}
+	if err != nil {
+		return err
+	}
+	printSuccess("对象复制成功")
+	return nil
+}
+
+// sameProfile 判断两个配置名是否指向同一个配置，空名称表示当前配置
+func sameProfile(a, b string) (bool, error) {
+	a, err := config.ResolveProfileName(a)
+	if err != nil {
+		return false, err
+	}
+	b, err = config.ResolveProfileName(b)
+	if err != nil {
+		return false, err
+	}
+	return a == b, nil
+}
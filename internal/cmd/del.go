@@ -11,11 +11,18 @@ import (
 var delCmd = &cobra.Command{
 	Use:   "del [s3://bucketname/path/file]",
 	Short: "删除 S3 存储中的对象",
-	Long:  `删除指定的 S3 对象或文件夹。`,
-	Args:  cobra.ExactArgs(1),
+	Long: `删除指定的 S3 对象或文件夹。
+
+删除文件夹时可以使用 --include 和 --exclude 按相对路径筛选要删除的对象。
+指定 --keep-going 时单个对象删除失败后继续删除其余对象，失败的对象名称写入清单，
+之后可以用 --from-file 只重试这些对象。部分对象失败时退出码为 2。
+
+示例:
+  s3ctl del s3://mybucket/logs/ --include '**/*.log'`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// 创建 S3 客户端
-		client, err := s3client.NewClient(cmd.Context(), false)
+		client, err := newClient(cmd.Context(), "", false)
 		if err != nil {
 			return err
 		}
@@ -30,19 +37,34 @@ var delCmd = &cobra.Command{
 		// 检查对象是否为文件夹
 		if client.IsDirectory(s3Path) {
 			// 递归删除文件夹中的所有对象
-			fmt.Printf("正在递归删除文件夹 %s/%s...\n", bucketName, objectPath)
-			if err := client.DeleteDirectory(bucketName, objectPath); err != nil {
+			filter, err := buildFilter()
+			if err != nil {
+				return err
+			}
+			only, err := readManifest(false)
+			if err != nil {
 				return err
 			}
-			fmt.Println("文件夹删除成功")
+			deleteOpts := s3client.DeleteOptions{Filter: filter, KeepGoing: keepGoing, Only: only}
+
+			fmt.Printf("正在递归删除文件夹 %s/%s...\n", bucketName, objectPath)
+			if err := client.DeleteDirectory(bucketName, objectPath, deleteOpts); err != nil {
+				return reportFailures(cmd, err)
+			}
+			printSuccess("文件夹删除成功")
 		} else {
 			// 删除单个对象
 			if err := client.DeleteObject(bucketName, objectPath); err != nil {
 				return err
 			}
-			fmt.Println("对象删除成功")
+			printSuccess("对象删除成功")
 		}
 
 		return nil
 	},
 }
+
+func init() {
+	addFilterFlags(delCmd)
+	addKeepGoingFlags(delCmd)
+}
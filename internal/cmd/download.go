@@ -10,6 +10,13 @@ import (
 	"github.com/zboyco/s3ctl/internal/s3client"
 )
 
+var (
+	connections      int
+	downloadJobs     int
+	downloadChecksum string
+	downloadPreserve bool
+)
+
 // downloadCmd represents the download command
 var downloadCmd = &cobra.Command{
 	Use:   "download <s3://bucket/path> [local-path]",
@@ -28,11 +35,30 @@ var downloadCmd = &cobra.Command{
 
   下载目录到指定目录
   s3ctl download s3://mybucket/path/to/dir/ ./local/dir/
+
+  使用 16 个工作协程并发下载目录
+  s3ctl download s3://mybucket/path/to/dir/ ./local/dir/ --jobs 16
+
+  使用 8 个连接并发下载大文件
+  s3ctl download s3://mybucket/path/to/large.iso ./ --connections 8
+
+  下载后校验对象的 SHA256 附加校验和
+  s3ctl download s3://mybucket/path/to/file.txt ./ --checksum sha256
+
+  只下载目录中的日志文件
+  s3ctl download s3://mybucket/path/to/dir/ ./local/dir/ --include '**/*.log'
+
+  恢复上传时以 put --preserve 保存的修改时间和权限
+  s3ctl download s3://mybucket/path/to/dir/ ./local/dir/ --preserve
+
+  单个对象失败后继续下载其余对象，之后只重试失败的对象
+  s3ctl download s3://mybucket/path/to/dir/ ./local/dir/ --keep-going
+  s3ctl download s3://mybucket/path/to/dir/ ./local/dir/ --keep-going --from-file s3ctl-failed.txt
 `,
 	Args: cobra.RangeArgs(1, 2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// 创建 S3 客户端
-		client, err := s3client.NewClient(cmd.Context(), useV2)
+		client, err := newClient(cmd.Context(), "", useV2)
 		if err != nil {
 			return err
 		}
@@ -51,15 +77,39 @@ var downloadCmd = &cobra.Command{
 			return fmt.Errorf("无效的路径格式，请使用 s3://bucket/object")
 		}
 
+		filter, err := buildFilter()
+		if err != nil {
+			return err
+		}
+		checksum, err := s3client.ParseChecksum(downloadChecksum)
+		if err != nil {
+			return err
+		}
+		only, err := readManifest(false)
+		if err != nil {
+			return err
+		}
+		downloadOpts := s3client.DownloadOptions{
+			Connections: connections,
+			Jobs:        downloadJobs,
+			Filter:      filter,
+			Checksum:    checksum,
+			KeepGoing:   keepGoing,
+			Only:        only,
+			Preserve:    downloadPreserve,
+		}
+
 		// 确定本地路径
 		localPath := "."
 		if len(args) == 2 {
 			localPath = args[1]
 		}
 
-		// 确保目录存在
-		if err := os.MkdirAll(filepath.Dir(localPath), 0o755); err != nil {
-			return fmt.Errorf("创建目录失败: %w", err)
+		// 确保目录存在，演练模式下不创建
+		if !dryRun {
+			if err := os.MkdirAll(filepath.Dir(localPath), 0o755); err != nil {
+				return fmt.Errorf("创建目录失败: %w", err)
+			}
 		}
 
 		// 判断是文件还是目录
@@ -67,10 +117,10 @@ var downloadCmd = &cobra.Command{
 
 		if isDir {
 			// 下载目录
-			if err := client.DownloadDirectory(bucketName, objectPath, localPath); err != nil {
-				return err
+			if err := client.DownloadDirectory(bucketName, objectPath, localPath, downloadOpts); err != nil {
+				return reportFailures(cmd, err)
 			}
-			fmt.Printf("目录下载成功")
+			printSuccess("目录下载成功")
 		} else {
 			// 下载文件
 
@@ -80,11 +130,20 @@ var downloadCmd = &cobra.Command{
 				localPath = filepath.Join(localPath, fileName)
 			}
 
-			if err := client.DownloadFile(bucketName, objectPath, localPath); err != nil {
+			if err := client.DownloadFile(bucketName, objectPath, localPath, downloadOpts); err != nil {
 				return err
 			}
-			fmt.Printf("文件下载成功")
+			printSuccess("文件下载成功")
 		}
 		return nil
 	},
 }
+
+func init() {
+	downloadCmd.Flags().IntVarP(&downloadJobs, "jobs", "j", 1, "下载目录时同时下载的对象数")
+	downloadCmd.Flags().IntVar(&connections, "connections", 1, "单个对象的并发下载连接数，大文件会按字节范围拆分下载")
+	downloadCmd.Flags().StringVar(&downloadChecksum, "checksum", "", "下载后按对象存储的附加校验和校验文件 (sha256, crc32c, crc64nvme)，不一致时删除临时文件")
+	downloadCmd.Flags().BoolVar(&downloadPreserve, "preserve", false, "恢复上传时以 --preserve 保存的修改时间、权限、属主和属组，非 root 用户忽略属主")
+	addFilterFlags(downloadCmd)
+	addKeepGoingFlags(downloadCmd)
+}
@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/zboyco/s3ctl/internal/s3client"
+)
+
+var (
+	includePatterns []string
+	excludePatterns []string
+)
+
+// addFilterFlags 为批量操作的命令注册 --include 和 --exclude
+func addFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&includePatterns, "include", nil, "只处理相对路径匹配该模式的文件，支持 ** (例如：**/*.log)，可重复指定")
+	cmd.Flags().StringArrayVar(&excludePatterns, "exclude", nil, "跳过相对路径匹配该模式的文件，支持 ** (例如：node_modules/**)，可重复指定")
+}
+
+// buildFilter 根据 --include 和 --exclude 构建筛选器
+func buildFilter() (*s3client.Filter, error) {
+	return s3client.NewFilter(includePatterns, excludePatterns)
+}
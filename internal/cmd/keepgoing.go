@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/zboyco/s3ctl/internal/s3client"
+)
+
+// defaultFailedManifest 继续执行模式下默认写入失败清单的文件
+const defaultFailedManifest = "s3ctl-failed.txt"
+
+var (
+	keepGoing      bool
+	failedManifest string
+	fromFile       string
+)
+
+// addKeepGoingFlags 为批量操作的命令注册 --keep-going、--failed-manifest 和 --from-file
+func addKeepGoingFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&keepGoing, "keep-going", false, "单个文件或对象失败后继续处理其余的，结束后输出失败列表并写入失败清单")
+	cmd.Flags().StringVar(&failedManifest, "failed-manifest", defaultFailedManifest, "继续执行模式下写入失败的文件路径或对象名称的清单文件")
+	cmd.Flags().StringVar(&fromFile, "from-file", "", "只处理清单文件中列出的文件路径或对象名称，例如 --failed-manifest 写入的清单")
+}
+
+// readManifest 读取 --from-file 指定的清单，每行一个文件路径或对象名称，忽略空行。
+// localPaths 为 true 时按相对于上传目录、以 / 分隔的路径规范化，未指定 --from-file 时返回 nil，表示处理全部
+func readManifest(localPaths bool) (map[string]bool, error) {
+	if fromFile == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(fromFile)
+	if err != nil {
+		return nil, fmt.Errorf("读取清单文件失败: %w", err)
+	}
+
+	only := map[string]bool{}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if line == "" {
+			continue
+		}
+		if localPaths {
+			line = path.Clean(filepath.ToSlash(line))
+		}
+		only[line] = true
+	}
+	return only, nil
+}
+
+// reportFailures 部分失败时输出失败列表并写入失败清单，返回原错误。
+// 部分失败不是参数错误，不再输出命令用法
+func reportFailures(cmd *cobra.Command, err error) error {
+	var partial *s3client.PartialError
+	if !errors.As(err, &partial) {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	fmt.Println("\n失败列表:")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, failure := range partial.Failures {
+		fmt.Fprintf(w, "  %s\t%s\n", failure.Name, strings.ReplaceAll(failure.Err.Error(), "\n", "; "))
+	}
+	w.Flush()
+
+	content := strings.Join(partial.Names(), "\n") + "\n"
+	if writeErr := os.WriteFile(failedManifest, []byte(content), 0o644); writeErr != nil {
+		return errors.Join(err, fmt.Errorf("写入失败清单失败: %w", writeErr))
+	}
+	fmt.Printf("失败清单已写入 %s，可以使用 --from-file %s 重试\n", failedManifest, failedManifest)
+	return err
+}
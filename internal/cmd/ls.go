@@ -22,7 +22,8 @@ var listCmd = &cobra.Command{
 	Long: `列出 S3 存储桶或指定桶中的对象。
 - 不带参数时列出所有存储桶
 - 指定桶名时列出该桶中的对象
-- 可选指定前缀筛选对象`,
+- 可选指定前缀筛选对象
+- 可使用 --include 和 --exclude 按相对于前缀的路径筛选对象`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// 创建 S3 客户端
@@ -60,8 +61,13 @@ var listCmd = &cobra.Command{
 			bucketName = input
 		}
 
+		filter, err := buildFilter()
+		if err != nil {
+			return err
+		}
+
 		// 列出桶中的对象
-		err = listBucketObjects(client, bucketName, prefix, recursive, onlyFolders, showFullPath)
+		err = listBucketObjects(client, bucketName, prefix, recursive, onlyFolders, showFullPath, filter)
 		if minio.ToErrorResponse(err).Code == "NoSuchBucket" {
 			fmt.Printf("存储桶 %s 不存在\n", bucketName)
 			return nil
@@ -74,6 +80,7 @@ func init() {
 	listCmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "递归列出所有对象")
 	listCmd.Flags().BoolVarP(&onlyFolders, "folders", "f", false, "只列出文件夹")
 	listCmd.Flags().BoolVarP(&showFullPath, "full-path", "p", false, "显示完整路径") // 注册新参数
+	addFilterFlags(listCmd)
 }
 
 // listAllBuckets 列出所有桶
@@ -103,7 +110,7 @@ func listAllBuckets(client *s3client.Client, prefix string) error {
 }
 
 // listBucketObjects 列出桶中的对象
-func listBucketObjects(client *s3client.Client, bucketName, prefix string, recursive, onlyFolders, showFullPath bool) error {
+func listBucketObjects(client *s3client.Client, bucketName, prefix string, recursive, onlyFolders, showFullPath bool, filter *s3client.Filter) error {
 	fullPrefix := fmt.Sprintf("s3://%s/%s", bucketName, prefix)
 	if !strings.HasSuffix(fullPrefix, "/") {
 		// fullPrefix 保留最后一个 /前的部分
@@ -120,6 +127,11 @@ func listBucketObjects(client *s3client.Client, bucketName, prefix string, recur
 			return object.Err
 		}
 
+		// 跳过未通过筛选的对象
+		if !filter.MatchKey(prefix, object.Key) {
+			continue
+		}
+
 		if object.Key[len(object.Key)-1] != '/' || object.Key != prefix {
 
 			// 完整路径
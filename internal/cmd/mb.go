@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"github.com/spf13/cobra"
-	"github.com/zboyco/s3ctl/internal/s3client"
 	"github.com/zboyco/s3ctl/internal/utils"
 )
 
@@ -12,7 +11,7 @@ var mbCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// 创建 S3 客户端
-		client, err := s3client.NewClient(cmd.Context(), false)
+		client, err := newClient(cmd.Context(), "", false)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/zboyco/s3ctl/internal/s3client"
+	"github.com/zboyco/s3ctl/internal/utils"
+)
+
+var (
+	mirrorDelete bool
+	mirrorReport string
+)
+
+var mirrorCmd = &cobra.Command{
+	Use:   "mirror [profile:]s3://source-bucket/prefix/ [profile:]s3://target-bucket/prefix/",
+	Short: "在存储桶之间镜像对象",
+	Long: `将源存储桶或前缀镜像到目标存储桶或前缀，只复制目标端缺失或不同的对象，
+保留用户元数据、Content-Type 和标签，复制后校验目标对象的大小和 ETag。
+
+两端为同一配置时在服务端复制，路径前加上不同的配置名时在两个服务之间流式传输。
+单个对象失败时继续处理其余对象，结束后汇总复制、跳过、删除和失败的数量。
+
+指定 --delete 时删除目标端源端不存在的对象。指定 --report 时将每个对象的操作
+(copy、skip、delete、error) 以及源端和目标端的 ETag 以 JSON Lines 格式写入文件，
+便于迁移后审计。指定 --dry-run 时不写入报告。
+
+示例:
+  s3ctl mirror s3://src-bucket s3://dst-bucket
+  s3ctl mirror minio:s3://assets/ oss:s3://assets/ --delete --report mirror.jsonl`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// 解析源路径和目标路径
+		srcProfile, srcBucket, srcPrefix, err := utils.ParseProfileS3Path(args[0])
+		if err != nil {
+			return err
+		}
+		dstProfile, dstBucket, dstPrefix, err := utils.ParseProfileS3Path(args[1])
+		if err != nil {
+			return err
+		}
+
+		same, err := sameProfile(srcProfile, dstProfile)
+		if err != nil {
+			return err
+		}
+
+		// 创建 S3 客户端
+		srcClient, err := newClient(cmd.Context(), srcProfile, false)
+		if err != nil {
+			return err
+		}
+		dstClient := srcClient
+		if !same {
+			if dstClient, err = newClient(cmd.Context(), dstProfile, false); err != nil {
+				return err
+			}
+		}
+
+		// --dry-run 时只输出计划，不创建或覆盖报告文件
+		mirrorOpts := s3client.MirrorOptions{Delete: mirrorDelete}
+		if mirrorReport != "" && !dryRun {
+			file, err := os.Create(mirrorReport)
+			if err != nil {
+				return fmt.Errorf("创建报告文件失败: %w", err)
+			}
+			defer file.Close()
+			mirrorOpts.Report = file
+		}
+
+		result, err := srcClient.Mirror(dstClient, srcBucket, srcPrefix, dstBucket, dstPrefix, mirrorOpts)
+		fmt.Printf("镜像完成: 复制 %d 个，跳过 %d 个，删除 %d 个，失败 %d 个\n", result.Copied, result.Skipped, result.Deleted, result.Failed)
+		return err
+	},
+}
+
+func init() {
+	mirrorCmd.Flags().BoolVar(&mirrorDelete, "delete", false, "删除目标端源端不存在的对象")
+	mirrorCmd.Flags().StringVar(&mirrorReport, "report", "", "将每个对象的处理结果以 JSON Lines 格式写入指定文件")
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zboyco/s3ctl/internal/s3client"
+	"github.com/zboyco/s3ctl/internal/utils"
+)
+
+var mvCmd = &cobra.Command{
+	Use:   "mv s3://bucket/source s3://bucket/target",
+	Short: "移动或重命名 S3 对象",
+	Long: `在服务端复制对象，校验目标对象的大小和 ETag 与源对象一致后再删除源对象。
+源路径以 / 结尾时移动整个前缀，复制或校验失败的对象会保留源对象，
+结束时汇总移动、失败和跳过的对象数量。
+
+示例:
+  s3ctl mv s3://mybucket/old-name.txt s3://mybucket/new-name.txt
+  s3ctl mv s3://mybucket/old-folder/ s3://mybucket/new-folder/`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// 创建 S3 客户端
+		client, err := newClient(cmd.Context(), "", false)
+		if err != nil {
+			return err
+		}
+
+		// 解析源路径和目标路径
+		srcBucket, srcObject, err := utils.ParseS3Path(args[0])
+		if err != nil {
+			return err
+		}
+		dstBucket, dstObject, err := utils.ParseS3Path(args[1])
+		if err != nil {
+			return err
+		}
+
+		// 源路径以 / 结尾时移动整个前缀
+		if srcObject == "" || strings.HasSuffix(srcObject, "/") {
+			result, err := client.MoveDirectory(srcBucket, srcObject, dstBucket, dstObject, s3client.CopyOptions{})
+			fmt.Printf("移动完成: 成功 %d 个，失败 %d 个，跳过 %d 个\n", result.Moved, result.Failed, result.Skipped)
+			return err
+		}
+
+		// 目标为前缀时使用源对象的文件名
+		if dstObject == "" || strings.HasSuffix(dstObject, "/") {
+			dstObject += path.Base(srcObject)
+		}
+
+		if err := client.MoveObject(srcBucket, srcObject, dstBucket, dstObject, s3client.CopyOptions{}); err != nil {
+			return err
+		}
+		printSuccess("对象移动成功")
+		return nil
+	},
+}
@@ -3,21 +3,68 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/zboyco/s3ctl/internal/s3client"
 	"github.com/zboyco/s3ctl/internal/utils"
 )
 
-var isPublic bool
+var (
+	isPublic    bool
+	partSize    string
+	partJobs    uint
+	resume      bool
+	putJobs     int
+	noIgnore    bool
+	putChecksum string
+	putSymlinks string
+	putPreserve bool
+	contentType string
+	putHeaders  []string
+	putMetadata []string
+	headerRules string
+)
 
 var putCmd = &cobra.Command{
-	Use:   "put [file/directory] [s3://bucketname/newpath/file.jpg]",
+	Use:   "put [file/directory/-] [profile:]s3://bucketname/newpath/file.jpg",
 	Short: "上传文件或目录到 S3 存储",
-	Args:  cobra.ExactArgs(2),
+	Long: `上传文件或目录到 S3 存储。
+
+源路径为 - 时从标准输入读取数据，以分片方式上传大小未知的数据流，
+内存占用为 --part-size 乘以 --part-jobs。
+
+目标路径前可以加上配置名，上传到非当前配置的服务；源路径也可以是带配置名的
+S3 路径，此时数据从源服务流式写入目标服务，不落地到本地磁盘，只支持 --content-type 和 --metadata 覆盖目标对象的属性。
+
+上传目录时会读取根目录及各级子目录中的 .s3ctlignore，按 .gitignore 语法 (包括 ! 取反)
+跳过匹配的文件和目录，使用 --no-ignore 可以忽略这些规则。
+
+上传目录时 --symlinks 指定符号链接的处理方式：follow (默认) 上传链接指向的文件并进入链接指向的目录，
+跳过指向上级目录形成循环的链接；skip 跳过所有符号链接；preserve 将链接保存为零字节对象，
+目标路径记录在 x-amz-meta-symlink-target 元数据中，download 时重新创建为符号链接。
+
+上传目录时指定 --keep-going，单个文件失败后继续上传其余文件，结束后输出失败列表，
+并将失败的文件路径写入 --failed-manifest 指定的清单，之后可以用 --from-file 只重试这些文件。
+部分文件失败时退出码为 2。
+
+--header 设置 Cache-Control、Content-Disposition、Content-Encoding、Content-Language、Content-Type
+和 Expires，--metadata 设置 x-amz-meta-* 用户元数据，都可以重复指定。--header-rules 指定的规则文件
+每行为一个 glob 模式和一个 HTTP 头，按相对于上传目录的路径为匹配的文件设置，命令行指定的值优先。
+
+示例:
+  pg_dump mydb | gzip | s3ctl put - s3://backups/db.gz
+  s3ctl put ./build oss:s3://static/site/
+  s3ctl put minio:s3://bucket/data.bin oss:s3://bucket/data.bin
+  s3ctl put ./project s3://bucket/project/ --exclude 'node_modules/**' --exclude '**/*.log'
+  s3ctl put ./photos s3://bucket/photos/ --keep-going
+  s3ctl put ./photos s3://bucket/photos/ --keep-going --from-file s3ctl-failed.txt
+  s3ctl put ./report.pdf s3://bucket/report.pdf --header 'Content-Disposition: attachment' --metadata owner=ops
+  s3ctl put ./site s3://static/site/ --header-rules headers.rules`,
+	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// 创建 S3 客户端
-		client, err := s3client.NewClient(cmd.Context(), false)
+		// 解析上传选项
+		uploadOpts, err := buildUploadOptions()
 		if err != nil {
 			return err
 		}
@@ -25,13 +72,40 @@ var putCmd = &cobra.Command{
 		// 获取文件或目录路径
 		localPath := args[0]
 
-		// 解析 S3 路径
+		// 源路径为 S3 路径时在两个配置之间复制
+		if utils.IsS3Path(localPath) {
+			copyOpts, err := copyOptionsForPut(cmd)
+			if err != nil {
+				return err
+			}
+			return copyS3Path(cmd.Context(), localPath, args[1], copyOpts)
+		}
+
+		// 解析 S3 路径，可以带配置名前缀
 		s3Path := args[1]
-		bucketName, objectPath, err := utils.ParseS3Path(s3Path)
+		profile, bucketName, objectPath, err := utils.ParseProfileS3Path(s3Path)
+		if err != nil {
+			return err
+		}
+
+		// 创建 S3 客户端
+		client, err := newClient(cmd.Context(), profile, false)
 		if err != nil {
 			return err
 		}
 
+		// 从标准输入上传
+		if localPath == "-" {
+			if resume {
+				return fmt.Errorf("从标准输入上传时不支持 --resume")
+			}
+			if err := client.UploadStream(bucketName, objectPath, os.Stdin, uploadOpts); err != nil {
+				return err
+			}
+			printSuccess("文件上传成功")
+			return nil
+		}
+
 		// 判断是文件还是目录
 		isDir, err := isDirectory(localPath)
 		if err != nil {
@@ -39,18 +113,28 @@ var putCmd = &cobra.Command{
 		}
 
 		if isDir {
-			// 上传目录
-			fmt.Printf("正在上传目录 %s 到 %s/%s...\n", localPath, bucketName, objectPath)
-			if err := client.UploadDirectory(bucketName, localPath, objectPath, isPublic); err != nil {
+			// 上传目录，--from-file 时只上传清单中的文件
+			uploadOpts.KeepGoing = keepGoing
+			if uploadOpts.Only, err = readManifest(true); err != nil {
 				return err
 			}
-			fmt.Println("目录上传成功")
+
+			fmt.Printf("正在上传目录 %s 到 %s/%s...\n", localPath, bucketName, objectPath)
+			if putJobs > 1 {
+				err = client.UploadDirectoryConcurrent(bucketName, localPath, objectPath, uploadOpts, putJobs)
+			} else {
+				err = client.UploadDirectory(bucketName, localPath, objectPath, uploadOpts)
+			}
+			if err != nil {
+				return reportFailures(cmd, err)
+			}
+			printSuccess("目录上传成功")
 		} else {
 			// 上传文件
-			if err := client.UploadFile(bucketName, localPath, objectPath, isPublic); err != nil {
+			if err := client.UploadFile(bucketName, localPath, objectPath, uploadOpts); err != nil {
 				return err
 			}
-			fmt.Println("文件上传成功")
+			printSuccess("文件上传成功")
 		}
 
 		return nil
@@ -59,6 +143,109 @@ var putCmd = &cobra.Command{
 
 func init() {
 	putCmd.Flags().BoolVarP(&isPublic, "public", "p", false, "上传为公开文件")
+	putCmd.Flags().IntVarP(&putJobs, "jobs", "j", 1, "上传目录时同时上传的文件数")
+	putCmd.Flags().StringVar(&partSize, "part-size", "", "分片上传的分片大小（例如：16MiB, 64MiB），默认自动计算")
+	putCmd.Flags().UintVar(&partJobs, "part-jobs", 0, "单个文件分片并发上传数，默认由 minio-go 决定")
+	putCmd.Flags().BoolVar(&resume, "resume", false, "记录上传进度，中断后再次执行时只上传缺失的分片")
+	putCmd.Flags().BoolVar(&noIgnore, "no-ignore", false, "上传目录时不读取 .s3ctlignore 忽略规则")
+	putCmd.Flags().StringVar(&putChecksum, "checksum", "", "以附加校验和上传，由服务端校验收到的数据 (sha256, crc32c, crc64nvme)")
+	putCmd.Flags().StringVar(&putSymlinks, "symlinks", string(s3client.SymlinkFollow), "上传目录时符号链接的处理方式 (follow, skip, preserve)")
+	putCmd.Flags().BoolVar(&putPreserve, "preserve", false, "在元数据中保存文件的修改时间、权限、属主和属组，download --preserve 时恢复")
+	putCmd.Flags().StringVar(&contentType, "content-type", "", "指定上传对象的 Content-Type，默认按配置中的 content_types、扩展名或文件内容识别")
+	putCmd.Flags().StringArrayVar(&putHeaders, "header", nil, "设置 HTTP 头，格式为 'Name: value' 或 Name=value，可重复指定")
+	putCmd.Flags().StringArrayVar(&putMetadata, "metadata", nil, "设置 x-amz-meta-* 用户元数据，格式为 key=value，可重复指定")
+	putCmd.Flags().StringVar(&headerRules, "header-rules", "", "按 glob 模式为匹配的文件设置 HTTP 头的规则文件")
+	addFilterFlags(putCmd)
+	addKeepGoingFlags(putCmd)
+}
+
+// buildUploadOptions 根据命令行参数构建上传选项
+func buildUploadOptions() (s3client.UploadOptions, error) {
+	opts := s3client.UploadOptions{
+		IsPublic:    isPublic,
+		PartJobs:    partJobs,
+		Resume:      resume,
+		NoIgnore:    noIgnore,
+		Preserve:    putPreserve,
+		ContentType: contentType,
+	}
+
+	filter, err := buildFilter()
+	if err != nil {
+		return opts, err
+	}
+	opts.Filter = filter
+
+	checksum, err := s3client.ParseChecksum(putChecksum)
+	if err != nil {
+		return opts, err
+	}
+	opts.Checksum = checksum
+
+	for _, s := range putHeaders {
+		header, err := s3client.ParseHeader(s)
+		if err != nil {
+			return opts, err
+		}
+		opts.Headers = append(opts.Headers, header)
+	}
+	for _, s := range putMetadata {
+		header, err := s3client.ParseMetadata(s)
+		if err != nil {
+			return opts, err
+		}
+		opts.Headers = append(opts.Headers, header)
+	}
+	if headerRules != "" {
+		if opts.HeaderRules, err = s3client.LoadHeaderRules(headerRules); err != nil {
+			return opts, err
+		}
+	}
+
+	symlinks, err := s3client.ParseSymlinkMode(putSymlinks)
+	if err != nil {
+		return opts, err
+	}
+	opts.Symlinks = symlinks
+
+	if partSize != "" {
+		size, err := utils.ParseSize(partSize)
+		if err != nil {
+			return opts, fmt.Errorf("解析分片大小失败: %w", err)
+		}
+		opts.PartSize = uint64(size)
+	}
+
+	if err := opts.Validate(); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
+
+// copyOptionsForPut 在源路径为 S3 路径时将 --content-type 和 --metadata 转换为复制选项，
+// 其余只适用于本地上传的参数被显式指定时返回错误，避免静默忽略
+func copyOptionsForPut(cmd *cobra.Command) (s3client.CopyOptions, error) {
+	var unsupported []string
+	for _, name := range []string{
+		"public", "jobs", "part-size", "part-jobs", "resume", "no-ignore", "checksum", "symlinks",
+		"preserve", "header", "header-rules", "include", "exclude", "keep-going", "failed-manifest", "from-file",
+	} {
+		if cmd.Flags().Changed(name) {
+			unsupported = append(unsupported, "--"+name)
+		}
+	}
+	if len(unsupported) > 0 {
+		return s3client.CopyOptions{}, fmt.Errorf("源路径为 S3 路径时不支持 %s", strings.Join(unsupported, ", "))
+	}
+
+	metadata, err := utils.ParseKeyValues(putMetadata)
+	if err != nil {
+		return s3client.CopyOptions{}, err
+	}
+	return s3client.CopyOptions{
+		ContentType: contentType,
+		Metadata:    metadata,
+	}, nil
 }
 
 // isDirectory 判断路径是否为目录
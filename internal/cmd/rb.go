@@ -4,7 +4,6 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
-	"github.com/zboyco/s3ctl/internal/s3client"
 	"github.com/zboyco/s3ctl/internal/utils"
 )
 
@@ -14,7 +13,7 @@ var rbCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// 创建 S3 客户端
-		client, err := s3client.NewClient(cmd.Context(), false)
+		client, err := newClient(cmd.Context(), "", false)
 		if err != nil {
 			return err
 		}
@@ -31,7 +30,7 @@ var rbCmd = &cobra.Command{
 			return err
 		}
 
-		fmt.Printf("存储桶 %s 删除成功\n", bucketName)
+		printSuccess(fmt.Sprintf("存储桶 %s 删除成功", bucketName))
 		return nil
 	},
 }
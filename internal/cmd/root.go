@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,6 +10,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/zboyco/s3ctl" // 导入 s3ctl 包
+	"github.com/zboyco/s3ctl/internal/s3client"
 )
 
 var rootCmd = &cobra.Command{
@@ -20,11 +22,27 @@ var rootCmd = &cobra.Command{
 	// Version: s3ctl.Version(), // 直接在这里设置也可以，但通常在 init 中设置
 }
 
+// 命令的退出码
+const (
+	ExitFailure        = 1 // 命令失败
+	ExitPartialFailure = 2 // 继续执行模式下部分文件或对象失败
+)
+
 // Execute 执行根命令
 func Execute(ctx context.Context) error {
 	return rootCmd.ExecuteContext(ctx)
 }
 
+// ExitCode 返回命令错误对应的退出码。继续执行模式下只有部分文件或对象失败时返回
+// ExitPartialFailure，全部失败时与其他错误相同
+func ExitCode(err error) int {
+	var partial *s3client.PartialError
+	if errors.As(err, &partial) && partial.Succeeded > 0 {
+		return ExitPartialFailure
+	}
+	return ExitFailure
+}
+
 func init() {
 	// 设置版本号
 	rootCmd.Version = s3ctl.Version() // 从 s3ctl 包获取版本信息
@@ -38,6 +56,18 @@ func init() {
 	rootCmd.AddCommand(rbCmd)
 	rootCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(downloadCmd)
+	rootCmd.AddCommand(catCmd)
+	rootCmd.AddCommand(cpCmd)
+	rootCmd.AddCommand(mvCmd)
+	rootCmd.AddCommand(syncCmd)
+	rootCmd.AddCommand(mirrorCmd)
+
+	// 全局选项
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "只列出将要执行的上传、覆盖、删除和创建操作，不实际执行")
+	rootCmd.PersistentFlags().StringVar(&progress, "progress", string(s3client.ProgressAuto), "进度显示方式：auto（终端中显示进度条，否则逐行输出）、bar、plain、json（向标准错误逐行输出 JSON 事件）或 none")
+	rootCmd.PersistentFlags().StringVar(&limitRate, "limit-rate", "", "所有并发传输的总速度上限（例如：20MiB/s），0 表示不限速，默认使用配置中的 limit_rate")
+	rootCmd.PersistentFlags().IntVar(&retries, "retries", s3client.DefaultRetries, "单个对象遇到暂时性错误时的最多重试次数，0 表示不重试，默认使用配置中的 retries")
+	rootCmd.PersistentFlags().DurationVar(&retryMaxWait, "retry-max-wait", s3client.DefaultRetryMaxWait, "两次重试之间的最长等待时间，默认使用配置中的 retry_max_wait")
 
 	// 禁用 help 和 completion 命令
 	rootCmd.SetHelpCommand(&cobra.Command{
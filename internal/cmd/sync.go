@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/zboyco/s3ctl/internal/s3client"
+	"github.com/zboyco/s3ctl/internal/utils"
+)
+
+var (
+	syncChecksum bool
+	syncDelete   bool
+	syncPublic   bool
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync <source> <target>",
+	Short: "在本地目录与 S3 前缀之间同步",
+	Long: `在本地目录与 S3 前缀之间同步，只传输新增或变化的文件。源路径为本地目录时上传，
+源路径为 S3 路径时下载。
+
+默认按大小和修改时间判断文件是否变化。上传时大小不同，或本地修改时间晚于对象的
+最后修改时间时重新上传；下载后本地文件的修改时间会设置为对象的最后修改时间，
+再次下载时大小或修改时间不同即重新下载。指定 --checksum 时比较文件 MD5 与对象 ETag，
+分片上传的对象无法按 ETag 比较，仍按大小和修改时间判断。
+
+指定 --delete 时删除目标端多余的对象或文件。
+
+示例:
+  s3ctl sync ./site s3://static/site/
+  s3ctl sync ./data s3://backup/data/ --checksum --delete
+  s3ctl sync s3://configs/app/ /etc/app --delete`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		syncOpts := s3client.SyncOptions{
+			Checksum: syncChecksum,
+			Delete:   syncDelete,
+			Upload:   s3client.UploadOptions{IsPublic: syncPublic},
+		}
+
+		// 源路径为 S3 路径时从远程下载
+		if utils.IsS3Path(args[0]) {
+			if utils.IsS3Path(args[1]) {
+				return fmt.Errorf("目标路径必须是本地目录，在存储桶之间同步请使用 mirror")
+			}
+			return syncFromRemote(cmd.Context(), args[0], args[1], syncOpts)
+		}
+		return syncToRemote(cmd.Context(), args[0], args[1], syncOpts)
+	},
+}
+
+func init() {
+	syncCmd.Flags().BoolVar(&syncChecksum, "checksum", false, "按 MD5 与 ETag 判断文件是否变化")
+	syncCmd.Flags().BoolVar(&syncDelete, "delete", false, "删除目标端多余的对象或文件")
+	syncCmd.Flags().BoolVarP(&syncPublic, "public", "p", false, "上传时设为公开文件")
+}
+
+// syncToRemote 将本地目录同步到 S3 前缀
+func syncToRemote(ctx context.Context, dirPath, s3Path string, syncOpts s3client.SyncOptions) error {
+	// 解析 S3 路径，可以带配置名前缀
+	profile, bucketName, prefix, err := utils.ParseProfileS3Path(s3Path)
+	if err != nil {
+		return err
+	}
+
+	// 创建 S3 客户端
+	client, err := newClient(ctx, profile, false)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("正在同步 %s 到 %s/%s...\n", dirPath, bucketName, prefix)
+	result, err := client.SyncToRemote(dirPath, bucketName, prefix, syncOpts)
+	fmt.Printf("同步完成: 上传 %d 个，跳过 %d 个，删除 %d 个\n", result.Uploaded, result.Skipped, result.Deleted)
+	return err
+}
+
+// syncFromRemote 将 S3 前缀同步到本地目录
+func syncFromRemote(ctx context.Context, s3Path, dirPath string, syncOpts s3client.SyncOptions) error {
+	// 解析 S3 路径，可以带配置名前缀
+	profile, bucketName, prefix, err := utils.ParseProfileS3Path(s3Path)
+	if err != nil {
+		return err
+	}
+
+	// 创建 S3 客户端
+	client, err := newClient(ctx, profile, false)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("正在同步 %s/%s 到 %s...\n", bucketName, prefix, dirPath)
+	result, err := client.SyncFromRemote(bucketName, prefix, dirPath, syncOpts)
+	fmt.Printf("同步完成: 下载 %d 个，跳过 %d 个，删除 %d 个\n", result.Downloaded, result.Skipped, result.Deleted)
+	return err
+}
@@ -2,13 +2,16 @@ package config
 
 import (
 	"fmt"
+	"mime"
 	"net/url"
 	"os"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/spf13/viper"
+	"github.com/zboyco/s3ctl/internal/utils"
 )
 
 // S3Config S3 配置
@@ -18,12 +21,16 @@ type S3Config struct {
 }
 
 type S3ConfigItem struct {
-	Endpoint        string `mapstructure:"endpoint" validate:"required"`
-	AccessKeyID     string `mapstructure:"access_key_id" validate:"required,min=3"`
-	SecretAccessKey string `mapstructure:"secret_access_key" validate:"required,min=8"`
-	UseSSL          bool   `mapstructure:"use_ssl"`
-	Region          string `mapstructure:"region"`
-	Timeout         int    `mapstructure:"timeout" validate:"omitempty,min=1,max=300"`
+	Endpoint        string            `mapstructure:"endpoint" validate:"required"`
+	AccessKeyID     string            `mapstructure:"access_key_id" validate:"required,min=3"`
+	SecretAccessKey string            `mapstructure:"secret_access_key" validate:"required,min=8"`
+	UseSSL          bool              `mapstructure:"use_ssl"`
+	Region          string            `mapstructure:"region"`
+	Timeout         int               `mapstructure:"timeout" validate:"omitempty,min=1,max=300"`
+	LimitRate       string            `mapstructure:"limit_rate"`                                 // 默认的传输限速，例如 20MiB/s，为空表示不限速
+	Retries         *int              `mapstructure:"retries" validate:"omitempty,min=0,max=100"` // 单个对象失败后的重试次数，为空时使用默认值
+	RetryMaxWait    string            `mapstructure:"retry_max_wait"`                             // 两次重试之间的最长等待时间，例如 30s
+	ContentTypes    map[string]string `mapstructure:"content_types"`                              // 按扩展名覆盖上传时的 Content-Type，例如 .md: text/markdown
 }
 
 // Validate 验证配置项
@@ -33,6 +40,27 @@ func (c *S3ConfigItem) Validate() error {
 		return err
 	}
 
+	if c.LimitRate != "" {
+		if _, err := utils.ParseRate(c.LimitRate); err != nil {
+			return fmt.Errorf("limit_rate 格式无效: %w", err)
+		}
+	}
+
+	if c.RetryMaxWait != "" {
+		if wait, err := time.ParseDuration(c.RetryMaxWait); err != nil || wait <= 0 {
+			return fmt.Errorf("retry_max_wait 格式无效: %s", c.RetryMaxWait)
+		}
+	}
+
+	for ext, contentType := range c.ContentTypes {
+		if strings.TrimPrefix(ext, ".") == "" {
+			return fmt.Errorf("content_types 中的扩展名不能为空")
+		}
+		if mediaType, _, err := mime.ParseMediaType(contentType); err != nil || !strings.Contains(mediaType, "/") {
+			return fmt.Errorf("content_types 中 %s 的 Content-Type 无效: %s", ext, contentType)
+		}
+	}
+
 	return validateEndpoint(c.Endpoint)
 }
 
@@ -131,6 +159,43 @@ func GetCurrentS3ConfigItem() (*S3ConfigItem, error) {
 	return item, nil
 }
 
+// GetS3ConfigItem 获取指定名称的 S3 配置项，名称为空时返回当前使用的配置项
+func GetS3ConfigItem(name string) (*S3ConfigItem, error) {
+	if name == "" {
+		return GetCurrentS3ConfigItem()
+	}
+
+	config, err := GetS3Config()
+	if err != nil {
+		return nil, err
+	}
+
+	item, ok := config.Services[name]
+	if !ok || item == nil {
+		return nil, fmt.Errorf("配置 '%s' 不存在，请使用 's3ctl config list' 查看可用的配置", name)
+	}
+
+	// 验证配置
+	if err := item.Validate(); err != nil {
+		return nil, fmt.Errorf("配置 '%s' 验证失败: %w", name, err)
+	}
+
+	return item, nil
+}
+
+// ResolveProfileName 将空的配置名称解析为当前使用的配置名称
+func ResolveProfileName(name string) (string, error) {
+	if name != "" {
+		return name, nil
+	}
+
+	config, err := GetS3Config()
+	if err != nil {
+		return "", err
+	}
+	return config.Current, nil
+}
+
 // CreateDefaultConfig 创建默认配置文件
 func CreateDefaultConfig(configFile string) error {
 	// 配置文件模板，支持多配置项
@@ -7,6 +7,7 @@ import (
 )
 
 func TestS3ConfigItemValidate(t *testing.T) {
+	zero, negative := 0, -1
 	tests := []struct {
 		name    string
 		item    S3ConfigItem
@@ -79,6 +80,87 @@ func TestS3ConfigItemValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "limit rate with unit",
+			item: S3ConfigItem{
+				Endpoint:        "s3.example.com",
+				AccessKeyID:     "THISISKEYID",
+				SecretAccessKey: "THISISSECRETKEY",
+				LimitRate:       "20MiB/s",
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid limit rate",
+			item: S3ConfigItem{
+				Endpoint:        "s3.example.com",
+				AccessKeyID:     "THISISKEYID",
+				SecretAccessKey: "THISISSECRETKEY",
+				LimitRate:       "fast",
+			},
+			wantErr: true,
+		},
+		{
+			name: "retries and max wait",
+			item: S3ConfigItem{
+				Endpoint:        "s3.example.com",
+				AccessKeyID:     "THISISKEYID",
+				SecretAccessKey: "THISISSECRETKEY",
+				Retries:         &zero,
+				RetryMaxWait:    "1m",
+			},
+			wantErr: false,
+		},
+		{
+			name: "negative retries is invalid",
+			item: S3ConfigItem{
+				Endpoint:        "s3.example.com",
+				AccessKeyID:     "THISISKEYID",
+				SecretAccessKey: "THISISSECRETKEY",
+				Retries:         &negative,
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid retry max wait",
+			item: S3ConfigItem{
+				Endpoint:        "s3.example.com",
+				AccessKeyID:     "THISISKEYID",
+				SecretAccessKey: "THISISSECRETKEY",
+				RetryMaxWait:    "30",
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid content types",
+			item: S3ConfigItem{
+				Endpoint:        "s3.example.com",
+				AccessKeyID:     "THISISKEYID",
+				SecretAccessKey: "THISISSECRETKEY",
+				ContentTypes:    map[string]string{".md": "text/markdown; charset=utf-8", "wasm": "application/wasm"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid content type",
+			item: S3ConfigItem{
+				Endpoint:        "s3.example.com",
+				AccessKeyID:     "THISISKEYID",
+				SecretAccessKey: "THISISSECRETKEY",
+				ContentTypes:    map[string]string{".md": "markdown"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty content type extension",
+			item: S3ConfigItem{
+				Endpoint:        "s3.example.com",
+				AccessKeyID:     "THISISKEYID",
+				SecretAccessKey: "THISISSECRETKEY",
+				ContentTypes:    map[string]string{".": "text/plain"},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
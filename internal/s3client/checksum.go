@@ -0,0 +1,173 @@
+package s3client
+
+import (
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// ParseChecksum 解析校验和算法名称，支持 sha256、crc32c 和 crc64nvme，空字符串表示不校验。
+// crc32c 使用整个对象的校验和模式，分片上传后存储的仍是整个对象的 CRC32C
+func ParseChecksum(name string) (minio.ChecksumType, error) {
+	switch strings.ToLower(name) {
+	case "":
+		return minio.ChecksumNone, nil
+	case "sha256":
+		return minio.ChecksumSHA256, nil
+	case "crc32c":
+		return minio.ChecksumFullObjectCRC32C, nil
+	case "crc64nvme":
+		return minio.ChecksumCRC64NVME, nil
+	default:
+		return minio.ChecksumNone, fmt.Errorf("不支持的校验和算法: %s，可选值为 sha256、crc32c、crc64nvme", name)
+	}
+}
+
+// objectChecksum 返回对象存储的指定算法的校验和，没有时返回空字符串
+func objectChecksum(info minio.ObjectInfo, checksum minio.ChecksumType) string {
+	switch checksum.Base() {
+	case minio.ChecksumSHA256:
+		return info.ChecksumSHA256
+	case minio.ChecksumCRC32C:
+		return info.ChecksumCRC32C
+	case minio.ChecksumCRC64NVME:
+		return info.ChecksumCRC64NVME
+	}
+	return ""
+}
+
+// partChecksum 返回分片存储的指定算法的校验和
+func partChecksum(part *minio.ObjectAttributePart, checksum minio.ChecksumType) string {
+	switch checksum.Base() {
+	case minio.ChecksumSHA256:
+		return part.ChecksumSHA256
+	case minio.ChecksumCRC32C:
+		return part.ChecksumCRC32C
+	}
+	return ""
+}
+
+// checksumSection 需要校验的一段连续数据
+type checksumSection struct {
+	part int // 分片编号，0 表示整个对象
+	size int64
+	want string
+}
+
+// checksumSections 返回对象需要校验的各段数据。分片上传的对象存储的是各分片校验和的组合值
+// (以 -分片数 结尾)，此时按分片逐段校验，否则整个对象为一段
+func (c *Client) checksumSections(bucketName, objectName string, objInfo minio.ObjectInfo, checksum minio.ChecksumType) ([]checksumSection, error) {
+	want := objectChecksum(objInfo, checksum)
+	if !strings.Contains(want, "-") {
+		return []checksumSection{{size: objInfo.Size, want: want}}, nil
+	}
+
+	var (
+		sections []checksumSection
+		total    int64
+	)
+	marker := 0
+	for {
+		attrs, err := c.client.GetObjectAttributes(c.ctx, bucketName, objectName, minio.ObjectAttributesOptions{PartNumberMarker: marker})
+		if err != nil {
+			return nil, fmt.Errorf("获取分片校验和失败: %w", err)
+		}
+		for _, part := range attrs.ObjectParts.Parts {
+			sections = append(sections, checksumSection{
+				part: part.PartNumber,
+				size: int64(part.Size),
+				want: partChecksum(part, checksum),
+			})
+			total += int64(part.Size)
+		}
+		if !attrs.ObjectParts.IsTruncated {
+			break
+		}
+		marker = attrs.ObjectParts.NextPartNumberMarker
+	}
+
+	if total != objInfo.Size {
+		return nil, fmt.Errorf("分片大小合计 %d 与对象大小 %d 不一致", total, objInfo.Size)
+	}
+	return sections, nil
+}
+
+// checksumWriter 按顺序写入对象的数据，逐段计算校验和
+type checksumWriter struct {
+	checksum minio.ChecksumType
+	sections []checksumSection
+	hasher   hash.Hash
+	written  int64    // 当前段已写入的字节数
+	got      []string // 已写完的各段的校验和
+}
+
+func newChecksumWriter(checksum minio.ChecksumType, sections []checksumSection) *checksumWriter {
+	return &checksumWriter{checksum: checksum, sections: sections, hasher: checksum.Hasher()}
+}
+
+func (w *checksumWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		if len(w.got) == len(w.sections) {
+			return 0, fmt.Errorf("下载的数据超出对象大小")
+		}
+		chunk := min(int64(len(p)), w.sections[len(w.got)].size-w.written)
+		w.hasher.Write(p[:chunk])
+		w.written += chunk
+		p = p[chunk:]
+		w.endSections()
+	}
+	return n, nil
+}
+
+// endSections 结束已写满的段，空的段也在这里结束
+func (w *checksumWriter) endSections() {
+	for len(w.got) < len(w.sections) && w.written == w.sections[len(w.got)].size {
+		w.got = append(w.got, minio.NewChecksum(w.checksum, w.hasher.Sum(nil)).Encoded())
+		w.hasher.Reset()
+		w.written = 0
+	}
+}
+
+// verify 比较各段计算出的校验和与对象存储的校验和
+func (w *checksumWriter) verify() error {
+	w.endSections()
+	if len(w.got) < len(w.sections) {
+		return fmt.Errorf("下载的数据不完整，无法校验 %s 校验和", w.checksum)
+	}
+	for i, section := range w.sections {
+		if w.got[i] == section.want {
+			continue
+		}
+		if section.part == 0 {
+			return fmt.Errorf("%s 校验和不一致: 对象为 %s，下载的文件为 %s", w.checksum, section.want, w.got[i])
+		}
+		return fmt.Errorf("分片 %d 的 %s 校验和不一致: 对象为 %s，下载的文件为 %s", section.part, w.checksum, section.want, w.got[i])
+	}
+	return nil
+}
+
+// verifyChecksum 重新读取已写入磁盘的文件，校验与对象存储的校验和是否一致。只用于无法在下载时
+// 计算的情况: 多连接下载的各范围乱序写入，续传时之前下载的部分也不经过本次下载的数据流
+func (c *Client) verifyChecksum(bucketName, objectName string, objInfo minio.ObjectInfo, filePath string, checksum minio.ChecksumType) error {
+	sections, err := c.checksumSections(bucketName, objectName, objInfo, checksum)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("打开文件失败: %w", err)
+	}
+	defer file.Close()
+
+	sums := newChecksumWriter(checksum, sections)
+	if _, err := io.Copy(sums, file); err != nil {
+		return fmt.Errorf("计算校验和失败: %w", err)
+	}
+	return sums.verify()
+}
@@ -2,6 +2,7 @@ package s3client
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"mime"
@@ -12,9 +13,11 @@ import (
 	"sync"
 	"time"
 
+	"github.com/gabriel-vasile/mimetype"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
 	"github.com/zboyco/s3ctl/internal/config"
+	"github.com/zboyco/s3ctl/internal/utils"
 	"golang.org/x/term"
 )
 
@@ -25,19 +28,69 @@ const (
 	DefaultMinBarWidth     = 20
 	DefaultBufferSize      = 100
 	MaxBufferSize          = 1000
+	MinPartSize            = 5 << 20 // S3 允许的最小分片大小
+	MaxPartSize            = 5 << 30 // S3 允许的最大分片大小
 )
 
+// UploadOptions 上传选项
+type UploadOptions struct {
+	IsPublic bool               // 上传为公开文件
+	PartSize uint64             // 分片大小（字节），0 表示由 minio-go 自动计算
+	PartJobs uint               // 分片并发上传数，0 表示使用 minio-go 默认值
+	Resume   bool               // 记录分片上传状态，中断后可续传
+	Filter   *Filter            // 上传目录时按相对路径筛选文件，nil 表示上传全部文件
+	NoIgnore bool               // 上传目录时不读取 .s3ctlignore
+	Checksum minio.ChecksumType // 以附加校验和上传，服务端校验收到的数据，ChecksumNone 表示不使用
+
+	KeepGoing bool            // 上传目录时单个文件失败后继续上传其余文件，结束后返回 PartialError
+	Only      map[string]bool // 上传目录时只上传其中列出的文件，路径相对于上传目录并以 / 分隔，nil 表示全部
+	Symlinks  SymlinkMode     // 上传目录时符号链接的处理方式，空值与 follow 相同
+	Preserve  bool            // 在元数据中保存文件的修改时间、权限、属主和属组
+
+	ContentType string       // 指定上传对象的 Content-Type，为空时按扩展名或文件内容识别
+	Headers     []Header     // 设置到每个对象的 HTTP 头和用户元数据，优先于 HeaderRules
+	HeaderRules *HeaderRules // 按相对于上传目录的路径为匹配的文件设置 HTTP 头，上传单个文件时按文件名匹配
+}
+
+// Validate 检查上传选项是否合法
+func (o UploadOptions) Validate() error {
+	if o.PartSize > 0 && (o.PartSize < MinPartSize || o.PartSize > MaxPartSize) {
+		return fmt.Errorf("分片大小必须在 %s 到 %s 之间", formatBytes(MinPartSize), formatBytes(MaxPartSize))
+	}
+	if o.ContentType != "" {
+		if mediaType, _, err := mime.ParseMediaType(o.ContentType); err != nil || !strings.Contains(mediaType, "/") {
+			return fmt.Errorf("无效的 Content-Type: %s", o.ContentType)
+		}
+	}
+	if o.Checksum.IsSet() && o.Resume {
+		return fmt.Errorf("附加校验和暂不支持与续传同时使用")
+	}
+	return nil
+}
+
 // Client S3 客户端
 type Client struct {
-	client *minio.Client
+	client  *minio.Client
+	trailer *minio.Client // 启用了尾部校验头的客户端，用于带附加校验和的上传
 
-	ctx context.Context
+	ctx      context.Context
+	dryRun   bool         // 演练模式，只输出将要执行的写操作
+	limiter  *rateLimiter // 所有传输共享的限速器，nil 表示不限速
+	retry    RetryPolicy  // 单个对象传输失败后的重试策略
+	progress ProgressMode // 进度显示方式，空值与 auto 相同
+
+	contentTypes map[string]string // 配置中按扩展名覆盖的 Content-Type，键为带点的小写扩展名
 }
 
 // NewClient 创建 S3 客户端
 func NewClient(ctx context.Context, v2 bool) (*Client, error) {
+	return NewClientWithProfile(ctx, "", v2)
+}
+
+// NewClientWithProfile 使用指定名称的配置创建 S3 客户端，名称为空时使用当前配置
+func NewClientWithProfile(ctx context.Context, profile string, v2 bool) (*Client, error) {
 	// 获取配置
-	cfg, err := config.GetCurrentS3ConfigItem()
+	cfg, err := config.GetS3ConfigItem(profile)
 	if err != nil {
 		return nil, fmt.Errorf("获取配置失败: %w", err)
 	}
@@ -58,9 +111,42 @@ func NewClient(ctx context.Context, v2 bool) (*Client, error) {
 		return nil, fmt.Errorf("创建 S3 客户端失败: %w", err)
 	}
 
+	// 附加校验和需要以尾部校验头发送，默认的客户端不启用，避免改变普通上传的请求
+	trailer, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:           creds,
+		Secure:          cfg.UseSSL,
+		TrailingHeaders: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建 S3 客户端失败: %w", err)
+	}
+
+	// 配置中的默认限速，可以被 SetRateLimit 覆盖
+	var limitRate int64
+	if cfg.LimitRate != "" {
+		if limitRate, err = utils.ParseRate(cfg.LimitRate); err != nil {
+			return nil, fmt.Errorf("解析限速失败: %w", err)
+		}
+	}
+
+	// 配置中的重试策略，未设置的项使用默认值
+	retry := DefaultRetryPolicy()
+	if cfg.Retries != nil {
+		retry.Retries = *cfg.Retries
+	}
+	if cfg.RetryMaxWait != "" {
+		if retry.MaxWait, err = time.ParseDuration(cfg.RetryMaxWait); err != nil {
+			return nil, fmt.Errorf("解析重试最长等待时间失败: %w", err)
+		}
+	}
+
 	return &Client{
-		client: client,
-		ctx:    ctx,
+		client:       client,
+		trailer:      trailer,
+		ctx:          ctx,
+		limiter:      newRateLimiter(limitRate),
+		retry:        retry,
+		contentTypes: extensionTypes(cfg.ContentTypes),
 	}, nil
 }
 
@@ -75,6 +161,19 @@ func (c *Client) ListBuckets() ([]minio.BucketInfo, error) {
 
 // MakeBucket 创建存储桶
 func (c *Client) MakeBucket(bucketName string) error {
+	if c.dryRun {
+		exists, err := c.client.BucketExists(c.ctx, bucketName)
+		if err != nil {
+			return fmt.Errorf("检查存储桶失败: %w", err)
+		}
+		if exists {
+			fmt.Printf("存储桶 '%s' 已存在\n", bucketName)
+			return nil
+		}
+		planAction(dryRunCreate, "存储桶 "+bucketName, -1)
+		return nil
+	}
+
 	err := c.client.MakeBucket(c.ctx, bucketName, minio.MakeBucketOptions{})
 	if err != nil {
 		// 检查桶是否已存在
@@ -101,6 +200,11 @@ func (c *Client) RemoveBucket(bucketName string) error {
 		return nil
 	}
 
+	if c.dryRun {
+		planAction(dryRunDelete, "存储桶 "+bucketName, -1)
+		return nil
+	}
+
 	err = c.client.RemoveBucket(c.ctx, bucketName)
 	if err != nil {
 		return fmt.Errorf("删除存储桶失败: %w", err)
@@ -131,8 +235,39 @@ func (c *Client) IsBucketEmpty(bucketName string) (bool, error) {
 }
 
 // UploadFile 上传文件
-func (c *Client) UploadFile(bucketName, filePath, objectName string, isPublic bool) error {
+func (c *Client) UploadFile(bucketName, filePath, objectName string, uploadOpts UploadOptions) error {
+	if c.dryRun {
+		return c.planUpload(bucketName, filePath, objectName)
+	}
 	fmt.Printf("上传 %s 到 %s/%s...\n", filePath, bucketName, objectName)
+	return c.uploadFile(bucketName, filePath, objectName, uploadOpts, c.fileProgress(filePath))
+}
+
+// uploadFile 上传文件，上传进度由 newProgress 创建的跟踪器接收，暂时性的错误按重试策略重试
+func (c *Client) uploadFile(bucketName, filePath, objectName string, uploadOpts UploadOptions, newProgress progressFunc) error {
+	// 如果未指定对象名称，则使用文件名
+	if objectName == "" {
+		objectName = filepath.Base(filePath)
+	}
+
+	// 保留符号链接时上传链接本身
+	if uploadOpts.Symlinks == SymlinkPreserve {
+		if target, err := os.Readlink(filePath); err == nil {
+			return c.withRetry(bucketName+"/"+objectName, newProgress, func(newProgress progressFunc) error {
+				return c.uploadSymlink(bucketName, objectName, target, uploadOpts, newProgress)
+			})
+		}
+	}
+
+	return c.withRetry(bucketName+"/"+objectName, newProgress, func(newProgress progressFunc) error {
+		return c.uploadFileOnce(bucketName, filePath, objectName, uploadOpts, newProgress)
+	})
+}
+
+// uploadFileOnce 执行一次文件上传
+func (c *Client) uploadFileOnce(bucketName, filePath, objectName string, uploadOpts UploadOptions, newProgress progressFunc) error {
+	uploadOpts = uploadOpts.forFile(filepath.Dir(filePath), filePath)
+
 	// 打开文件
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -146,26 +281,53 @@ func (c *Client) UploadFile(bucketName, filePath, objectName string, isPublic bo
 		return fmt.Errorf("获取文件信息失败: %w", err)
 	}
 
-	// 如果未指定对象名称，则使用文件名
-	if objectName == "" {
-		objectName = filepath.Base(filePath)
-	}
-
 	// 设置对象选项
 	opts := minio.PutObjectOptions{
-		ContentType: getContentType(filePath),
+		ContentType: c.contentType(filePath, uploadOpts, io.NewSectionReader(file, 0, fileInfo.Size())),
 	}
 
 	// 如果是公开文件，设置权限
-	if isPublic {
+	if uploadOpts.IsPublic {
 		opts.UserMetadata = map[string]string{"x-amz-acl": "public-read"}
 	}
 
+	// 保存文件属性
+	if uploadOpts.Preserve {
+		if opts.UserMetadata == nil {
+			opts.UserMetadata = make(map[string]string)
+		}
+		for k, v := range statAttrs(fileInfo).metadata() {
+			opts.UserMetadata[k] = v
+		}
+	}
+
+	// 命令行和规则文件指定的 HTTP 头与用户元数据
+	applyHeaders(&opts, uploadOpts.Headers)
+
+	// 分片大小与并发数，各分片的进度都会汇总到同一个进度条
+	opts.PartSize = uploadOpts.PartSize
+	opts.NumThreads = uploadOpts.PartJobs
+
+	// 大文件续传模式下自行管理分片上传
+	if uploadOpts.resumable(fileInfo.Size()) {
+		if err := c.uploadFileResumable(bucketName, filePath, objectName, file, fileInfo, opts, uploadOpts, newProgress); err != nil {
+			return fmt.Errorf("上传文件失败: %w", err)
+		}
+		return nil
+	}
+
 	// 添加上传进度跟踪
-	opts.Progress = newProgressReader(fileInfo.Size())
+	opts.Progress = c.throttled(newProgress(fileInfo.Size()))
+
+	// 附加校验和由 minio-go 在读取数据时计算，服务端校验不一致时拒绝写入
+	client := c.client
+	if uploadOpts.Checksum.IsSet() {
+		opts.Checksum = uploadOpts.Checksum
+		client = c.trailer
+	}
 
 	// 上传文件
-	_, err = c.client.PutObject(
+	_, err = client.PutObject(
 		c.ctx,
 		bucketName,
 		objectName,
@@ -180,70 +342,127 @@ func (c *Client) UploadFile(bucketName, filePath, objectName string, isPublic bo
 	return nil
 }
 
-// UploadDirectoryConcurrent 并发上传目录中的所有文件
-func (c *Client) UploadDirectoryConcurrent(bucketName, dirPath, prefix string, isPublic bool, maxWorkers int) error {
+// UploadDirectoryConcurrent 并发上传目录中的所有文件。任一文件上传失败后停止遍历目录，
+// 等待进行中的上传结束后汇总返回所有失败；继续执行模式下上传完所有文件后返回 PartialError
+func (c *Client) UploadDirectoryConcurrent(bucketName, dirPath, prefix string, uploadOpts UploadOptions, maxWorkers int) error {
 	if maxWorkers <= 0 {
 		maxWorkers = 4 // 默认 4 个工作协程
 	}
 
+	// 演练模式下逐个输出操作，避免与汇总进度显示交错
+	if c.dryRun {
+		return c.UploadDirectory(bucketName, dirPath, prefix, uploadOpts)
+	}
+
+	// 检查目录是否存在
+	info, err := os.Stat(dirPath)
+	if err != nil {
+		return fmt.Errorf("获取目录信息失败: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s 不是一个目录", dirPath)
+	}
+
+	ctx, cancel := context.WithCancel(c.ctx)
+	defer cancel()
+
+	var (
+		mu        sync.Mutex
+		failed    []error
+		failures  []Failure
+		succeeded int
+		wg        sync.WaitGroup
+	)
+
 	files := make(chan string, 100)
-	errors := make(chan error, maxWorkers)
+	display := newMultiProgress("上传", maxWorkers, c.progressMode())
 
 	// 启动工作协程
-	var wg sync.WaitGroup
 	for i := 0; i < maxWorkers; i++ {
+		slot := display.slot(i)
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
 			for filePath := range files {
-				if err := c.uploadSingleFile(bucketName, filePath, dirPath, prefix, isPublic); err != nil {
-					errors <- err
-					return
+				// 已停止时只消费剩余的文件路径
+				if ctx.Err() != nil {
+					continue
+				}
+
+				slot.start(filePath)
+				err := c.uploadSingleFile(bucketName, filePath, dirPath, prefix, uploadOpts, slot.tracker)
+				slot.finish(err)
+
+				mu.Lock()
+				switch {
+				case err == nil:
+					succeeded++
+				case uploadOpts.KeepGoing:
+					failures = append(failures, Failure{Name: manifestName(dirPath, filePath), Err: err})
+				default:
+					failed = append(failed, fmt.Errorf("上传文件 %s 失败: %w", filePath, err))
+					cancel()
 				}
+				mu.Unlock()
 			}
 		}()
 	}
 
-	// 遍历目录并发送文件路径
-	go func() {
-		defer close(files)
-		filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
-			if err != nil || info.IsDir() {
-				return err
-			}
-			files <- path
+	// 遍历目录并发送文件路径，停止后立即结束遍历
+	skipped, walkErr := walkUploadFiles(dirPath, uploadOpts, func(path string, info os.FileInfo) error {
+		display.addFile(info.Size())
+
+		select {
+		case files <- path:
 			return nil
-		})
-	}()
+		case <-ctx.Done():
+			return filepath.SkipAll
+		}
+	})
+	close(files)
+	wg.Wait()
+	display.skipped = skipped
+	display.stop()
 
-	// 等待完成
-	go func() {
-		wg.Wait()
-		close(errors)
-	}()
+	if walkErr != nil {
+		failed = append(failed, fmt.Errorf("遍历目录失败: %w", walkErr))
+	}
+	if err := c.ctx.Err(); err != nil {
+		failed = append(failed, fmt.Errorf("上传已取消: %w", err))
+	}
+	return partialResult("上传", succeeded, failures, failed)
+}
 
-	// 检查错误
-	for err := range errors {
-		if err != nil {
-			return err
-		}
+// uploadSingleFile 上传单个文件的辅助方法
+func (c *Client) uploadSingleFile(bucketName, filePath, dirPath, prefix string, uploadOpts UploadOptions, newProgress progressFunc) error {
+	objectName, err := objectNameFor(dirPath, filePath, prefix)
+	if err != nil {
+		return err
 	}
+	return c.uploadFile(bucketName, filePath, objectName, uploadOpts.forFile(dirPath, filePath), newProgress)
+}
 
-	return nil
+// manifestName 返回文件相对于上传目录、以 / 分隔的路径，用作失败清单和 --from-file 中的名称
+func manifestName(dirPath, filePath string) string {
+	rel, err := filepath.Rel(dirPath, filePath)
+	if err != nil {
+		return filePath
+	}
+	return filepath.ToSlash(rel)
 }
 
-// uploadSingleFile 上传单个文件的辅助方法
-func (c *Client) uploadSingleFile(bucketName, filePath, dirPath, prefix string, isPublic bool) error {
+// objectNameFor 根据文件相对于目录的路径计算对象名称
+func objectNameFor(dirPath, filePath, prefix string) (string, error) {
 	// 计算对象名称
 	relPath, err := filepath.Rel(dirPath, filePath)
 	if err != nil {
-		return fmt.Errorf("计算相对路径失败: %w", err)
+		return "", fmt.Errorf("计算相对路径失败: %w", err)
 	}
 
 	// 安全检查路径
 	safeRelPath, err := sanitizePath(relPath)
 	if err != nil {
-		return fmt.Errorf("路径安全检查失败: %w", err)
+		return "", fmt.Errorf("路径安全检查失败: %w", err)
 	}
 
 	// 替换 Windows 路径分隔符
@@ -256,19 +475,64 @@ func (c *Client) uploadSingleFile(bucketName, filePath, dirPath, prefix string,
 		// 替换 Windows 路径分隔符
 		objectName = strings.ReplaceAll(objectName, "\\", "/")
 	}
+	return objectName, nil
+}
 
-	return c.UploadFile(bucketName, filePath, objectName, isPublic)
+// localPathFor 根据对象相对于前缀的路径计算本地文件路径，拒绝会写到目录之外的对象名称
+func localPathFor(dirPath, prefix, objectName string) (string, error) {
+	relPath, err := sanitizePath(filepath.FromSlash(strings.TrimPrefix(objectName, prefix)))
+	if err != nil {
+		return "", fmt.Errorf("路径安全检查失败: %w", err)
+	}
+	if relPath == "." {
+		return "", fmt.Errorf("对象 %s 没有相对于前缀的路径", objectName)
+	}
+	return filepath.Join(dirPath, relPath), nil
+}
+
+// joinFailures 将多个失败汇总为一个错误
+func joinFailures(operation string, failed []error) error {
+	switch len(failed) {
+	case 0:
+		return nil
+	case 1:
+		return failed[0]
+	default:
+		return fmt.Errorf("%s过程中出现 %d 个错误:\n%w", operation, len(failed), errors.Join(failed...))
+	}
 }
 
-// getContentType 根据文件扩展名获取对应的 Content-Type
-func getContentType(filename string) string {
+// contentType 确定上传对象的 Content-Type，依次使用 UploadOptions.ContentType、配置中按扩展名的映射
+// 和系统的扩展名映射，扩展名未知时按 head 读取到的文件开头内容识别
+func (c *Client) contentType(filename string, uploadOpts UploadOptions, head io.Reader) string {
+	if uploadOpts.ContentType != "" {
+		return uploadOpts.ContentType
+	}
+
 	ext := strings.ToLower(filepath.Ext(filename))
+	if mimeType, ok := c.contentTypes[ext]; ok {
+		return mimeType
+	}
 	if mimeType := mime.TypeByExtension(ext); mimeType != "" {
 		return mimeType
 	}
+	if head != nil {
+		if mtype, err := mimetype.DetectReader(head); err == nil {
+			return mtype.String()
+		}
+	}
 	return "application/octet-stream"
 }
 
+// extensionTypes 将配置中的扩展名统一为带点的小写形式
+func extensionTypes(contentTypes map[string]string) map[string]string {
+	types := make(map[string]string, len(contentTypes))
+	for ext, mimeType := range contentTypes {
+		types["."+strings.TrimPrefix(strings.ToLower(ext), ".")] = mimeType
+	}
+	return types
+}
+
 // sanitizePath 清理路径，防止路径遍历攻击
 func sanitizePath(path string) (string, error) {
 	// 清理路径，防止路径遍历
@@ -299,6 +563,8 @@ type progressReader struct {
 	startTime    time.Time // 开始时间
 	lastBytes    int64     // 上次统计的字节数
 	lastTime     time.Time // 上次统计的时间
+	lastRefresh  time.Time // 总大小未知时上次打印的时间
+	drawn        bool      // 当前行已输出进度
 }
 
 func newProgressReader(totalSize int64) *progressReader {
@@ -348,7 +614,7 @@ func (pr *progressReader) Read(p []byte) (n int, err error) {
 	// 模拟读取数据
 	n = len(p)
 	if n > 0 {
-		pr.bytesRead += int64(n)
+		pr.add(int64(n))
 		pr.updateProgress()
 	}
 	return n, nil
@@ -364,12 +630,88 @@ func (pr *progressReader) Write(p []byte) (n int, err error) {
 	}
 
 	n = len(p)
-	pr.bytesRead += int64(n)
+	pr.add(int64(n))
 	pr.updateProgress()
 	return n, nil
 }
 
+// add 累加已传输的字节数。minio-go 内部重试请求时会重新读取数据，已知总大小时不超过总大小
+func (pr *progressReader) add(n int64) {
+	pr.bytesRead += n
+	if pr.totalSize >= 0 {
+		pr.bytesRead = min(pr.bytesRead, pr.totalSize)
+	}
+}
+
+// preset 将无需传输的字节计入进度，例如续传时已完成的部分
+func (pr *progressReader) preset(n int64) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	pr.bytesRead += n
+	pr.lastBytes += n
+}
+
+// restart 结束已输出的进度行并输出 msg，然后从头开始统计进度
+func (pr *progressReader) restart(msg string) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	if pr.drawn && !pr.completed {
+		fmt.Println()
+	}
+	fmt.Println(msg)
+
+	now := time.Now()
+	pr.bytesRead = 0
+	pr.lastPrint = 0
+	pr.lastBytes = 0
+	pr.completed = false
+	pr.drawn = false
+	pr.startTime = now
+	pr.lastTime = now
+}
+
+// done 结束进度显示。总大小未知时输出最终的传输量并换行，失败时结束已输出的进度行
+func (pr *progressReader) done(err error) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	switch {
+	case pr.completed:
+	case err == nil && pr.totalSize < 0:
+		pr.updateStreamProgress(true)
+	case pr.drawn:
+		fmt.Println()
+		pr.completed = true
+	}
+}
+
+// updateStreamProgress 总大小未知时只显示已传输字节数和平均速度
+func (pr *progressReader) updateStreamProgress(final bool) {
+	now := time.Now()
+	if !final && now.Sub(pr.lastRefresh) < DefaultRefreshInterval {
+		return
+	}
+	pr.lastRefresh = now
+
+	speed := float64(pr.bytesRead) / now.Sub(pr.startTime).Seconds()
+	fmt.Printf("\r已传输 %-12s %-12s", formatBytes(pr.bytesRead), fmt.Sprintf("%s/s", formatBytes(int64(speed))))
+	pr.drawn = true
+
+	if final {
+		fmt.Println()
+		pr.completed = true
+	}
+}
+
 func (pr *progressReader) updateProgress() {
+	// 总大小未知时（例如从标准输入上传）无法计算百分比
+	if pr.totalSize < 0 {
+		pr.updateStreamProgress(false)
+		return
+	}
+
 	// 计算当前进度百分比
 	percent := int64(float64(pr.bytesRead) / float64(pr.totalSize) * 100)
 
@@ -405,6 +747,7 @@ func (pr *progressReader) updateProgress() {
 			fmt.Sprintf("(%s/%s)", formatBytes(pr.bytesRead), formatBytes(pr.totalSize)),
 			fmt.Sprintf("%s/s", formatBytes(int64(speed))),
 			formatDuration(remainingTime))
+		pr.drawn = true
 
 		if percent == 100 {
 			fmt.Println()       // 上传完成后换行
@@ -448,7 +791,7 @@ func formatBytes(b int64) string {
 }
 
 // UploadDirectory 上传目录
-func (c *Client) UploadDirectory(bucketName, dirPath, prefix string, isPublic bool) error {
+func (c *Client) UploadDirectory(bucketName, dirPath, prefix string, uploadOpts UploadOptions) error {
 	// 检查目录是否存在
 	info, err := os.Stat(dirPath)
 	if err != nil {
@@ -459,108 +802,359 @@ func (c *Client) UploadDirectory(bucketName, dirPath, prefix string, isPublic bo
 	}
 
 	// 遍历目录
-	return filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+	var (
+		uploaded int
+		failures []Failure
+	)
+	skipped, err := walkUploadFiles(dirPath, uploadOpts, func(path string, info os.FileInfo) error {
+		// 计算对象名称并上传文件
+		objectName, err := objectNameFor(dirPath, path, prefix)
+		if err == nil {
+			err = c.UploadFile(bucketName, path, objectName, uploadOpts.forFile(dirPath, path))
+		}
 		if err != nil {
-			return err
+			// 继续执行模式下记录失败后处理下一个文件，取消时停止遍历
+			if !uploadOpts.KeepGoing {
+				return err
+			}
+			failures = append(failures, Failure{Name: manifestName(dirPath, path), Err: err})
+			return c.ctx.Err()
 		}
+		uploaded++
+		return nil
+	})
+	if err != nil {
+		return partialResult("上传", uploaded, failures, []error{err})
+	}
 
-		// 跳过目录
-		if info.IsDir() {
-			return nil
-		}
+	fmt.Printf("上传完成 %d 个文件%s\n", uploaded, skipped)
+	return partialResult("上传", uploaded, failures, nil)
+}
 
-		// 计算对象名称
-		relPath, err := filepath.Rel(dirPath, path)
-		if err != nil {
-			return fmt.Errorf("计算相对路径失败: %w", err)
-		}
+// skipCount 遍历目录时跳过的文件、目录和符号链接数
+type skipCount struct {
+	files int
+	dirs  int
+	links int
+}
 
-		// 安全检查路径
-		safeRelPath, err := sanitizePath(relPath)
-		if err != nil {
-			return fmt.Errorf("路径安全检查失败: %w", err)
-		}
+// String 返回追加在汇总信息之后的说明，没有跳过任何内容时返回空字符串
+func (s skipCount) String() string {
+	var text string
+	switch {
+	case s.dirs > 0:
+		text = fmt.Sprintf("，跳过 %d 个文件和 %d 个目录", s.files, s.dirs)
+	case s.files > 0:
+		text = fmt.Sprintf("，跳过 %d 个文件", s.files)
+	}
+	if s.links > 0 {
+		text += fmt.Sprintf("，跳过 %d 个符号链接", s.links)
+	}
+	return text
+}
 
-		// 替换 Windows 路径分隔符
-		safeRelPath = strings.ReplaceAll(safeRelPath, "\\", "/")
+// walkUploadFiles 遍历目录中需要上传的文件。未设置 NoIgnore 时读取各级目录中的
+// .s3ctlignore，跳过被忽略的文件和目录 (规则文件本身也不会上传)，再按 Filter 筛选文件。
+// 符号链接按 Symlinks 处理，fn 返回 filepath.SkipAll 时停止遍历
+func walkUploadFiles(dirPath string, uploadOpts UploadOptions, fn func(path string, info os.FileInfo) error) (skipCount, error) {
+	w := &uploadWalker{root: dirPath, opts: uploadOpts, fn: fn}
+	if !uploadOpts.NoIgnore {
+		w.ignore = newIgnoreMatcher()
+	}
 
-		// 构建对象名称
-		objectName := safeRelPath
-		if prefix != "" {
-			objectName = filepath.Join(prefix, safeRelPath)
-			// 替换 Windows 路径分隔符
-			objectName = strings.ReplaceAll(objectName, "\\", "/")
-		}
+	// 根目录本身是符号链接时总是跟随
+	info, err := os.Stat(dirPath)
+	if err != nil {
+		return w.skipped, err
+	}
+	err = w.dir(dirPath, ".", info)
+	if errors.Is(err, filepath.SkipAll) {
+		return w.skipped, nil
+	}
+	if err != nil || len(uploadOpts.Only) == 0 {
+		return w.skipped, err
+	}
+
+	// 清单中的路径相对于上传目录，全部不匹配时多半是清单与目录不对应
+	if w.listed == 0 {
+		return w.skipped, fmt.Errorf("清单中列出的 %d 个文件都不在 %s 中，清单中的路径应相对于上传目录", len(uploadOpts.Only), dirPath)
+	}
+	if missing := len(uploadOpts.Only) - w.listed; missing > 0 {
+		fmt.Printf("清单中有 %d 个文件不在 %s 中或已被忽略规则排除，已跳过\n", missing, dirPath)
+	}
+	return w.skipped, nil
+}
 
-		// 上传文件
-		return c.UploadFile(bucketName, path, objectName, isPublic)
+// DownloadFile 下载文件，数据先写入 .s3ctl-part 临时文件，完成后再重命名到目标路径，
+// 中断后再次下载时会从临时文件的已有长度继续
+func (c *Client) DownloadFile(bucketName, objectName, filePath string, downloadOpts DownloadOptions) error {
+	if c.dryRun {
+		return c.planDownload(bucketName, objectName, filePath)
+	}
+	fmt.Printf("下载 %s/%s 到 %s...\n", bucketName, objectName, filePath)
+	return c.downloadFile(bucketName, objectName, filePath, downloadOpts, c.fileProgress(objectName))
+}
+
+// downloadFile 下载文件，下载进度由 newProgress 创建的跟踪器接收。暂时性的错误按重试策略重试，
+// 重试时从临时文件中已下载的位置继续
+func (c *Client) downloadFile(bucketName, objectName, filePath string, downloadOpts DownloadOptions, newProgress progressFunc) error {
+	return c.withRetry(bucketName+"/"+objectName, newProgress, func(newProgress progressFunc) error {
+		return c.downloadFileOnce(bucketName, objectName, filePath, downloadOpts, newProgress)
 	})
 }
 
-// DownloadFile 下载文件
-func (c *Client) DownloadFile(bucketName, objectName, filePath string) error {
+// downloadFileOnce 执行一次文件下载
+func (c *Client) downloadFileOnce(bucketName, objectName, filePath string, downloadOpts DownloadOptions, newProgress progressFunc) error {
+	// 下载目录时不经由之前恢复的符号链接写到目录之外
+	if downloadOpts.root != "" {
+		if err := checkSymlinkParents(downloadOpts.root, filePath); err != nil {
+			return err
+		}
+	}
+
 	// 确保目录存在
 	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
 		return fmt.Errorf("创建目录失败: %w", err)
 	}
 
-	// 创建文件
-	file, err := os.Create(filePath)
+	// 获取对象信息以获取大小，需要校验时同时获取对象存储的校验和
+	objInfo, err := c.client.StatObject(c.ctx, bucketName, objectName, minio.StatObjectOptions{
+		Checksum: downloadOpts.Checksum.IsSet(),
+	})
 	if err != nil {
-		return fmt.Errorf("创建文件失败: %w", err)
+		return fmt.Errorf("获取对象信息失败: %w", err)
+	}
+	// 对象保存的是符号链接时在本地重新创建链接
+	if target := symlinkTarget(objInfo); target != "" {
+		newProgress(0)
+		if downloadOpts.links != nil {
+			downloadOpts.links.add(pendingLink{key: objectName, path: filePath, target: target})
+			return nil
+		}
+		return createSymlink(filePath, target)
+	}
+	if downloadOpts.Checksum.IsSet() && objectChecksum(objInfo, downloadOpts.Checksum) == "" {
+		return fmt.Errorf("对象 %s 没有 %s 校验和，无法校验", objectName, downloadOpts.Checksum)
 	}
-	defer file.Close()
 
-	// 获取对象信息以获取大小
-	objInfo, err := c.client.StatObject(c.ctx, bucketName, objectName, minio.StatObjectOptions{})
+	// 打开临时文件，ETag 未变化时从已下载的位置继续
+	part, err := openPartFile(filePath, objInfo)
 	if err != nil {
-		return fmt.Errorf("获取对象信息失败: %w", err)
+		return err
 	}
+	defer part.Close()
 
 	// 下载对象
-	fmt.Printf("下载 %s/%s 到 %s...\n", bucketName, objectName, filePath)
-	object, err := c.client.GetObject(c.ctx, bucketName, objectName, minio.GetObjectOptions{})
-	if err != nil {
-		return fmt.Errorf("获取对象失败: %w", err)
+	progress := c.throttled(newProgress(objInfo.Size))
+	progress.preset(part.offset)
+
+	// 大对象按范围拆分后多连接并发下载
+	if downloadOpts.useRanges(objInfo.Size - part.offset) {
+		if err := c.downloadRanges(bucketName, objectName, objInfo, part, downloadOpts.Connections, progress); err != nil {
+			return err
+		}
+		return c.finishDownload(bucketName, objectName, objInfo, part, downloadOpts, nil)
 	}
-	defer object.Close()
-	defer object.Close()
 
-	// 使用进度跟踪
-	progress := newProgressReader(objInfo.Size)
-	_, err = io.Copy(file, io.TeeReader(object, progress))
-	if err != nil {
-		return fmt.Errorf("下载文件失败: %w", err)
+	// 从头单连接下载时在写入的同时计算校验和，不需要下载后再读取一遍文件
+	var sums *checksumWriter
+	dst := io.Writer(part.file)
+	if downloadOpts.Checksum.IsSet() && part.offset == 0 {
+		sections, err := c.checksumSections(bucketName, objectName, objInfo, downloadOpts.Checksum)
+		if err != nil {
+			return err
+		}
+		sums = newChecksumWriter(downloadOpts.Checksum, sections)
+		dst = io.MultiWriter(part.file, sums)
 	}
 
+	if part.offset < objInfo.Size {
+		opts := minio.GetObjectOptions{}
+		if part.offset > 0 {
+			fmt.Printf("从 %s 处继续下载\n", formatBytes(part.offset))
+			if err := opts.SetRange(part.offset, 0); err != nil {
+				return fmt.Errorf("设置下载范围失败: %w", err)
+			}
+			if err := opts.SetMatchETag(objInfo.ETag); err != nil {
+				return fmt.Errorf("设置 ETag 校验失败: %w", err)
+			}
+		}
+
+		object, err := c.client.GetObject(c.ctx, bucketName, objectName, opts)
+		if err != nil {
+			return fmt.Errorf("获取对象失败: %w", err)
+		}
+		defer object.Close()
+
+		// 使用进度跟踪
+		if _, err := io.Copy(dst, io.TeeReader(object, progress)); err != nil {
+			return fmt.Errorf("下载文件失败: %w", err)
+		}
+	}
+
+	return c.finishDownload(bucketName, objectName, objInfo, part, downloadOpts, sums)
+}
+
+// finishDownload 按需校验临时文件的校验和后重命名到目标路径，校验失败时删除临时文件。
+// sums 为下载时已计算的校验和，nil 时重新读取临时文件计算
+func (c *Client) finishDownload(bucketName, objectName string, objInfo minio.ObjectInfo, part *partFile, downloadOpts DownloadOptions, sums *checksumWriter) error {
+	if downloadOpts.Checksum.IsSet() {
+		if err := part.Close(); err != nil {
+			return fmt.Errorf("写入文件失败: %w", err)
+		}
+		var err error
+		if sums != nil {
+			err = sums.verify()
+		} else {
+			err = c.verifyChecksum(bucketName, objectName, objInfo, part.path, downloadOpts.Checksum)
+		}
+		if err != nil {
+			if removeErr := part.remove(); removeErr != nil {
+				return errors.Join(err, removeErr)
+			}
+			return fmt.Errorf("%w，已删除临时文件 %s", err, part.path)
+		}
+	}
+	if err := part.commit(); err != nil {
+		return err
+	}
+
+	// 恢复上传时保存的文件属性
+	if downloadOpts.Preserve {
+		if err := objectAttrs(objInfo).restore(part.target); err != nil {
+			return fmt.Errorf("恢复文件属性失败: %w", err)
+		}
+	}
 	return nil
 }
 
-// DownloadDirectory 下载目录
-func (c *Client) DownloadDirectory(bucketName, prefix, dirPath string) error {
-	// 列出所有对象
+// DownloadDirectory 下载目录，使用固定数量的工作协程并发下载列出的对象。出现失败后不再分发新的对象，
+// 等待进行中的下载结束后汇总返回所有失败；继续执行模式下下载完所有对象后返回 PartialError
+func (c *Client) DownloadDirectory(bucketName, prefix, dirPath string, downloadOpts DownloadOptions) error {
+	jobs := max(downloadOpts.Jobs, 1)
+
+	// 符号链接在所有普通文件写入完成后再创建，避免其他工作协程在检查上级目录之后经由新建的链接写到目录之外
+	links := &linkQueue{}
+	downloadOpts.root = dirPath
+	downloadOpts.links = links
+
+	// 演练模式下逐个输出操作，避免与汇总进度显示交错
+	if c.dryRun {
+		jobs = 1
+	}
+
+	var (
+		mu        sync.Mutex
+		failed    []error
+		failures  []Failure
+		succeeded int
+		wg        sync.WaitGroup
+	)
+	addFailure := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		failed = append(failed, err)
+	}
+	// 记录单个对象的结果，继续执行模式下失败不会停止分发
+	addResult := func(key string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case err == nil:
+			succeeded++
+		case downloadOpts.KeepGoing:
+			failures = append(failures, Failure{Name: key, Err: err})
+		default:
+			failed = append(failed, fmt.Errorf("下载文件 %s 失败: %w", key, err))
+		}
+	}
+	hasFailure := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(failed) > 0
+	}
+
+	// 并发下载时使用汇总进度显示，避免多个进度条互相覆盖
+	var display *multiProgress
+	if jobs > 1 {
+		display = newMultiProgress("下载", jobs, c.progressMode())
+	}
+
+	// 启动工作协程
+	keys := make(chan string)
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for key := range keys {
+				// 计算本地文件路径
+				localPath, err := localPathFor(dirPath, prefix, key)
+				if err != nil {
+					addResult(key, err)
+					continue
+				}
+
+				// 下载文件
+				if display == nil {
+					err = c.DownloadFile(bucketName, key, localPath, downloadOpts)
+				} else {
+					slot := display.slot(i)
+					slot.start(key)
+					err = c.downloadFile(bucketName, key, localPath, downloadOpts, slot.tracker)
+					slot.finish(err)
+				}
+				addResult(key, err)
+			}
+		}(i)
+	}
+
+	// 列出所有对象并分发给工作协程，取消或失败后只消费剩余的列表结果
 	objects := c.ListObjects(bucketName, prefix, true, false)
 	for object := range objects {
 		if object.Err != nil {
-			return fmt.Errorf("列出对象失败: %w", object.Err)
+			addFailure(fmt.Errorf("列出对象失败: %w", object.Err))
+			continue
+		}
+
+		// 跳过目录标记以及未通过筛选的对象
+		if strings.HasSuffix(object.Key, "/") || !downloadOpts.Filter.MatchKey(prefix, object.Key) {
+			continue
+		}
+		if downloadOpts.Only != nil && !downloadOpts.Only[object.Key] {
+			continue
 		}
 
-		// 跳过目录标记
-		if strings.HasSuffix(object.Key, "/") {
+		if c.ctx.Err() != nil || hasFailure() {
 			continue
 		}
 
-		// 计算本地文件路径
-		relPath := strings.TrimPrefix(object.Key, prefix)
-		localPath := filepath.Join(dirPath, relPath)
+		if display != nil {
+			display.addFile(object.Size)
+		}
 
-		// 下载文件
-		if err := c.DownloadFile(bucketName, object.Key, localPath); err != nil {
-			return fmt.Errorf("下载文件 %s 失败: %w", object.Key, err)
+		select {
+		case keys <- object.Key:
+		case <-c.ctx.Done():
 		}
 	}
+	close(keys)
+	wg.Wait()
+	if display != nil {
+		display.stop()
+	}
 
-	return nil
+	// 按路径顺序创建符号链接，上级目录的链接先于其下的链接创建，其下的链接会被拒绝
+	for _, link := range links.sorted() {
+		if err := link.create(dirPath); err != nil {
+			succeeded--
+			addResult(link.key, err)
+		}
+	}
+
+	if err := c.ctx.Err(); err != nil {
+		failed = append(failed, fmt.Errorf("下载已取消: %w", err))
+	}
+	return partialResult("下载", succeeded, failures, failed)
 }
 
 // GenerateURL 生成访问 URL
@@ -1,9 +1,27 @@
 package s3client
 
 import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
 	"testing"
 	"time"
 
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -112,3 +130,1291 @@ func TestProgressReader(t *testing.T) {
 		assert.Equal(t, int64(1000), pr.bytesRead) // 50*10 + 50*10
 	})
 }
+
+func TestUploadOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    UploadOptions
+		wantErr bool
+	}{
+		{name: "default options", opts: UploadOptions{}},
+		{name: "valid part size", opts: UploadOptions{PartSize: 64 << 20, PartJobs: 8}},
+		{name: "part size too small", opts: UploadOptions{PartSize: 1 << 20}, wantErr: true},
+		{name: "part size too large", opts: UploadOptions{PartSize: 6 << 30}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUploadState(t *testing.T) {
+	t.Run("save and load", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "state.json")
+		state := &uploadState{
+			Bucket:   "mybucket",
+			Object:   "path/file.bin",
+			Size:     100 << 20,
+			ModTime:  time.Unix(1700000000, 0),
+			PartSize: 16 << 20,
+			UploadID: "upload-id",
+			Parts:    []uploadedPart{{PartNumber: 1, ETag: "etag-1", Size: 16 << 20}},
+		}
+		assert.NoError(t, state.save(path))
+
+		loaded, err := loadUploadState(path)
+		assert.NoError(t, err)
+		assert.Equal(t, state.UploadID, loaded.UploadID)
+		assert.Equal(t, state.Parts, loaded.Parts)
+		assert.True(t, loaded.ModTime.Equal(state.ModTime))
+	})
+
+	t.Run("missing state file", func(t *testing.T) {
+		loaded, err := loadUploadState(filepath.Join(t.TempDir(), "missing.json"))
+		assert.NoError(t, err)
+		assert.Nil(t, loaded)
+	})
+
+	t.Run("detect modified file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "data.bin")
+		assert.NoError(t, os.WriteFile(path, []byte("hello"), 0o644))
+		info, err := os.Stat(path)
+		assert.NoError(t, err)
+
+		state := &uploadState{Size: info.Size(), ModTime: info.ModTime()}
+		assert.True(t, state.matchesFile(info))
+
+		state.Size++
+		assert.False(t, state.matchesFile(info))
+	})
+}
+
+func TestUploadOptionsResumable(t *testing.T) {
+	assert.False(t, UploadOptions{}.resumable(1<<30))
+	assert.False(t, UploadOptions{Resume: true}.resumable(DefaultMultipartThreshold))
+	assert.True(t, UploadOptions{Resume: true}.resumable(DefaultMultipartThreshold+1))
+	assert.True(t, UploadOptions{Resume: true, PartSize: 8 << 20}.resumable(9<<20))
+}
+
+func TestPartFile(t *testing.T) {
+	objInfo := minio.ObjectInfo{ETag: "etag-1", Size: 10}
+
+	t.Run("resume when etag matches", func(t *testing.T) {
+		target := filepath.Join(t.TempDir(), "file.bin")
+		assert.NoError(t, os.WriteFile(target+partFileSuffix, []byte("hello"), 0o644))
+		assert.NoError(t, os.WriteFile(target+partFileSuffix+partETagSuffix, []byte(`"etag-1"`), 0o644))
+
+		part, err := openPartFile(target, objInfo)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), part.offset)
+
+		_, err = part.file.Write([]byte("world"))
+		assert.NoError(t, err)
+		assert.NoError(t, part.commit())
+
+		data, err := os.ReadFile(target)
+		assert.NoError(t, err)
+		assert.Equal(t, "helloworld", string(data))
+		assert.NoFileExists(t, target+partFileSuffix)
+		assert.NoFileExists(t, target+partFileSuffix+partETagSuffix)
+	})
+
+	t.Run("restart when etag changed", func(t *testing.T) {
+		target := filepath.Join(t.TempDir(), "file.bin")
+		assert.NoError(t, os.WriteFile(target+partFileSuffix, []byte("stale"), 0o644))
+		assert.NoError(t, os.WriteFile(target+partFileSuffix+partETagSuffix, []byte("etag-0"), 0o644))
+
+		part, err := openPartFile(target, objInfo)
+		assert.NoError(t, err)
+		defer part.Close()
+		assert.Equal(t, int64(0), part.offset)
+
+		info, err := os.Stat(target + partFileSuffix)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), info.Size())
+	})
+
+	t.Run("target untouched until commit", func(t *testing.T) {
+		target := filepath.Join(t.TempDir(), "file.bin")
+		assert.NoError(t, os.WriteFile(target, []byte("old"), 0o644))
+
+		part, err := openPartFile(target, objInfo)
+		assert.NoError(t, err)
+		assert.NoError(t, part.Close())
+
+		data, err := os.ReadFile(target)
+		assert.NoError(t, err)
+		assert.Equal(t, "old", string(data))
+	})
+
+	t.Run("remove deletes part and etag files", func(t *testing.T) {
+		target := filepath.Join(t.TempDir(), "file.bin")
+
+		part, err := openPartFile(target, objInfo)
+		assert.NoError(t, err)
+		assert.NoError(t, part.remove())
+
+		assert.NoFileExists(t, target+partFileSuffix)
+		assert.NoFileExists(t, target+partFileSuffix+partETagSuffix)
+	})
+}
+
+func TestSplitRanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		offset   int64
+		size     int64
+		n        int
+		expected []byteRange
+	}{
+		{
+			name: "even split",
+			size: 4 * MinRangeSize,
+			n:    2,
+			expected: []byteRange{
+				{start: 0, end: 2*MinRangeSize - 1},
+				{start: 2 * MinRangeSize, end: 4*MinRangeSize - 1},
+			},
+		},
+		{
+			name: "chunk not smaller than minimum",
+			size: 2 * MinRangeSize,
+			n:    8,
+			expected: []byteRange{
+				{start: 0, end: MinRangeSize - 1},
+				{start: MinRangeSize, end: 2*MinRangeSize - 1},
+			},
+		},
+		{
+			name:   "start from offset",
+			offset: 100,
+			size:   100 + 2*MinRangeSize + 1,
+			n:      2,
+			expected: []byteRange{
+				{start: 100, end: 100 + MinRangeSize},
+				{start: 101 + MinRangeSize, end: 100 + 2*MinRangeSize},
+			},
+		},
+		{
+			name:   "nothing remaining",
+			offset: 10,
+			size:   10,
+			n:      4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, splitRanges(tt.offset, tt.size, tt.n))
+		})
+	}
+}
+
+func TestJoinFailures(t *testing.T) {
+	assert.NoError(t, joinFailures("下载", nil))
+
+	single := fmt.Errorf("下载文件 a 失败")
+	assert.Equal(t, single, joinFailures("下载", []error{single}))
+
+	err := joinFailures("下载", []error{single, fmt.Errorf("下载文件 b 失败")})
+	assert.ErrorIs(t, err, single)
+	assert.Contains(t, err.Error(), "2 个错误")
+	assert.Contains(t, err.Error(), "下载文件 b 失败")
+}
+
+func TestPartialResult(t *testing.T) {
+	assert.NoError(t, partialResult("上传", 3, nil, nil))
+
+	cause := fmt.Errorf("打开文件失败")
+	failures := []Failure{{Name: "dir/a.txt", Err: cause}, {Name: "dir/b.txt", Err: fmt.Errorf("上传文件失败")}}
+
+	err := partialResult("上传", 3, failures, nil)
+	var partial *PartialError
+	assert.ErrorAs(t, err, &partial)
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, 3, partial.Succeeded)
+	assert.Equal(t, []string{"dir/a.txt", "dir/b.txt"}, partial.Names())
+	assert.Equal(t, "上传部分失败: 成功 3 个，失败 2 个", err.Error())
+
+	// 无法继续的错误按普通错误返回，同时保留已记录的失败
+	fatal := fmt.Errorf("遍历目录失败")
+	err = partialResult("上传", 3, failures, []error{fatal})
+	assert.False(t, errors.As(err, &partial))
+	assert.ErrorIs(t, err, fatal)
+	assert.ErrorIs(t, err, cause)
+	assert.Contains(t, err.Error(), "dir/b.txt")
+}
+
+func TestVerifyCopy(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     minio.ObjectInfo
+		dst     minio.ObjectInfo
+		wantErr bool
+	}{
+		{
+			name: "same size and etag",
+			src:  minio.ObjectInfo{Size: 10, ETag: `"abc"`},
+			dst:  minio.ObjectInfo{Size: 10, ETag: "abc"},
+		},
+		{
+			name:    "size mismatch",
+			src:     minio.ObjectInfo{Size: 10, ETag: "abc"},
+			dst:     minio.ObjectInfo{Size: 9, ETag: "abc"},
+			wantErr: true,
+		},
+		{
+			name:    "etag mismatch",
+			src:     minio.ObjectInfo{Size: 10, ETag: "abc"},
+			dst:     minio.ObjectInfo{Size: 10, ETag: "def"},
+			wantErr: true,
+		},
+		{
+			name: "multipart etag compares size only",
+			src:  minio.ObjectInfo{Size: 10, ETag: "abc-3"},
+			dst:  minio.ObjectInfo{Size: 10, ETag: "def"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyCopy(tt.src, tt.dst)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPutOptionsFromObject(t *testing.T) {
+	info := minio.ObjectInfo{
+		ContentType:  "text/html",
+		UserMetadata: map[string]string{"Owner": "web", "Env": "prod"},
+		Metadata: http.Header{
+			"Cache-Control":    []string{"no-cache"},
+			"Content-Encoding": []string{"gzip"},
+		},
+	}
+
+	opts := putOptionsFromObject(info, CopyOptions{})
+	assert.Equal(t, "text/html", opts.ContentType)
+	assert.Equal(t, "no-cache", opts.CacheControl)
+	assert.Equal(t, "gzip", opts.ContentEncoding)
+	assert.Equal(t, map[string]string{"Owner": "web", "Env": "prod"}, opts.UserMetadata)
+
+	opts = putOptionsFromObject(info, CopyOptions{ContentType: "text/plain", Metadata: map[string]string{"Env": "dev"}})
+	assert.Equal(t, "text/plain", opts.ContentType)
+	assert.Equal(t, map[string]string{"Owner": "web", "Env": "dev"}, opts.UserMetadata)
+	assert.Equal(t, "prod", info.UserMetadata["Env"])
+}
+
+func TestLocalChanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file.txt")
+	assert.NoError(t, os.WriteFile(path, []byte("hello"), 0o644))
+	modTime := time.Date(2024, 1, 1, 12, 0, 0, 500_000_000, time.UTC)
+	assert.NoError(t, os.Chtimes(path, modTime, modTime))
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+
+	// "hello" 的 MD5
+	const sum = "5d41402abc4b2a76b9719d911017c592"
+
+	tests := []struct {
+		name     string
+		object   minio.ObjectInfo
+		checksum bool
+		want     bool
+	}{
+		{
+			name:   "size differs",
+			object: minio.ObjectInfo{Size: 4, LastModified: modTime.Add(time.Hour)},
+			want:   true,
+		},
+		{
+			name:   "uploaded in the same second",
+			object: minio.ObjectInfo{Size: 5, LastModified: modTime.Truncate(time.Second)},
+		},
+		{
+			name:   "modified after upload",
+			object: minio.ObjectInfo{Size: 5, LastModified: modTime.Add(-time.Minute)},
+			want:   true,
+		},
+		{
+			name:     "checksum matches",
+			object:   minio.ObjectInfo{Size: 5, ETag: `"` + sum + `"`, LastModified: modTime.Add(-time.Minute)},
+			checksum: true,
+		},
+		{
+			name:     "checksum differs",
+			object:   minio.ObjectInfo{Size: 5, ETag: "0123", LastModified: modTime.Add(time.Hour)},
+			checksum: true,
+			want:     true,
+		},
+		{
+			name:     "multipart etag falls back to mtime",
+			object:   minio.ObjectInfo{Size: 5, ETag: "0123-2", LastModified: modTime.Add(time.Hour)},
+			checksum: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed, err := localChanged(path, info, tt.object, tt.checksum)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, changed)
+		})
+	}
+}
+
+func TestRemoteChanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file.txt")
+	assert.NoError(t, os.WriteFile(path, []byte("hello"), 0o644))
+	lastModified := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	assert.NoError(t, os.Chtimes(path, lastModified, lastModified))
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		object minio.ObjectInfo
+		want   bool
+	}{
+		{name: "unchanged", object: minio.ObjectInfo{Size: 5, LastModified: lastModified}},
+		{name: "object time has milliseconds", object: minio.ObjectInfo{Size: 5, LastModified: lastModified.Add(250 * time.Millisecond)}},
+		{name: "object replaced", object: minio.ObjectInfo{Size: 5, LastModified: lastModified.Add(time.Minute)}, want: true},
+		{name: "local file edited", object: minio.ObjectInfo{Size: 5, LastModified: lastModified.Add(-time.Minute)}, want: true},
+		{name: "size differs", object: minio.ObjectInfo{Size: 6, LastModified: lastModified}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed, err := remoteChanged(path, info, tt.object, false)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, changed)
+		})
+	}
+}
+
+func TestLocalPathFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		expected string
+		wantErr  bool
+	}{
+		{name: "nested key", key: "p/a/b.txt", expected: filepath.Join("dir", "a", "b.txt")},
+		{name: "parent traversal", key: "p/../../etc/passwd", wantErr: true},
+		{name: "absolute path", key: "p//etc/passwd", wantErr: true},
+		{name: "prefix itself", key: "p/", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := localPathFor("dir", "p/", tt.key)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestNeedsMirror(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		src  minio.ObjectInfo
+		dst  minio.ObjectInfo
+		want bool
+	}{
+		{
+			name: "same etag",
+			src:  minio.ObjectInfo{Size: 10, ETag: `"abc"`, LastModified: now},
+			dst:  minio.ObjectInfo{Size: 10, ETag: "abc", LastModified: now.Add(-time.Hour)},
+		},
+		{
+			name: "size differs",
+			src:  minio.ObjectInfo{Size: 10, ETag: "abc", LastModified: now},
+			dst:  minio.ObjectInfo{Size: 9, ETag: "abc", LastModified: now},
+			want: true,
+		},
+		{
+			name: "etag differs",
+			src:  minio.ObjectInfo{Size: 10, ETag: "abc", LastModified: now},
+			dst:  minio.ObjectInfo{Size: 10, ETag: "def", LastModified: now.Add(time.Hour)},
+			want: true,
+		},
+		{
+			name: "multipart etag with newer target",
+			src:  minio.ObjectInfo{Size: 10, ETag: "abc-2", LastModified: now},
+			dst:  minio.ObjectInfo{Size: 10, ETag: "def", LastModified: now.Add(time.Hour)},
+		},
+		{
+			name: "multipart etag with older target",
+			src:  minio.ObjectInfo{Size: 10, ETag: "abc-2", LastModified: now},
+			dst:  minio.ObjectInfo{Size: 10, ETag: "def", LastModified: now.Add(-time.Hour)},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, needsMirror(tt.src, tt.dst))
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	filter, err := NewFilter([]string{"**/*.log", "docs/**"}, []string{"node_modules/**", "**/debug.log"})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		rel  string
+		want bool
+	}{
+		{rel: "app.log", want: true},
+		{rel: "a/b/app.log", want: true},
+		{rel: "docs/index.md", want: true},
+		{rel: "main.go", want: false},
+		{rel: "node_modules/x/app.log", want: false},
+		{rel: "a/debug.log", want: false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, filter.Match(tt.rel), tt.rel)
+	}
+
+	// 对象名称按前缀计算相对路径，目录只检查排除模式
+	assert.True(t, filter.MatchKey("p/", "p/a/app.log"))
+	assert.False(t, filter.MatchKey("p/", "p/main.go"))
+	assert.True(t, filter.MatchKey("p/ap", "p/a/app.log"))
+	assert.True(t, filter.MatchKey("p/", "p/src/"))
+	assert.False(t, filter.MatchKey("p/", "p/node_modules/"))
+	assert.False(t, filter.MatchObject("p/", "p/"))
+	assert.False(t, filter.MatchObject("p/", "p/src/"))
+	assert.True(t, filter.MatchObject("p/", "p/docs/"))
+	assert.True(t, filter.MatchObject("p/", "p/a/app.log"))
+
+	// 未指定模式时不筛选
+	filter, err = NewFilter(nil, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, filter)
+	assert.True(t, filter.Match("anything"))
+
+	_, err = NewFilter([]string{"[a-"}, nil)
+	assert.Error(t, err)
+}
+
+// newTestClient 创建连接到测试服务器的客户端
+func newTestClient(t *testing.T, server *httptest.Server) *Client {
+	client, err := minio.New(strings.TrimPrefix(server.URL, "http://"), &minio.Options{
+		Creds:  credentials.NewStaticV4("id", "secret", ""),
+		Region: "us-east-1",
+	})
+	assert.NoError(t, err)
+	return &Client{client: client, ctx: context.Background()}
+}
+
+func TestDeleteDirectoryFilter(t *testing.T) {
+	keys := []string{"logs/", "logs/app.log", "logs/main.go", "logs/sub/", "logs/sub/err.log"}
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			deleted = append(deleted, strings.TrimPrefix(r.URL.Path, "/bucket/"))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		fmt.Fprint(w, `<ListBucketResult><Name>bucket</Name><IsTruncated>false</IsTruncated>`)
+		for _, key := range keys {
+			fmt.Fprintf(w, `<Contents><Key>%s</Key><Size>1</Size></Contents>`, key)
+		}
+		fmt.Fprint(w, `</ListBucketResult>`)
+	}))
+	defer server.Close()
+	c := newTestClient(t, server)
+
+	// 包含模式没有选中的目录标记不会被删除
+	filter, err := NewFilter([]string{"**/*.log"}, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, c.DeleteDirectory("bucket", "logs/", DeleteOptions{Filter: filter}))
+	assert.Equal(t, []string{"logs/app.log", "logs/sub/err.log"}, deleted)
+
+	deleted = nil
+	assert.NoError(t, c.DeleteDirectory("bucket", "logs/", DeleteOptions{}))
+	assert.Equal(t, keys, deleted)
+}
+
+func TestParseIgnoreRule(t *testing.T) {
+	tests := []struct {
+		line string
+		want ignoreRule
+		ok   bool
+	}{
+		{line: ""},
+		{line: "# comment"},
+		{line: ".DS_Store", want: ignoreRule{pattern: "**/.DS_Store"}, ok: true},
+		{line: "*.map  ", want: ignoreRule{pattern: "**/*.map"}, ok: true},
+		{line: ".git/", want: ignoreRule{pattern: "**/.git", dirOnly: true}, ok: true},
+		{line: "/dist/tmp", want: ignoreRule{pattern: "dist/tmp"}, ok: true},
+		{line: "!keep.map", want: ignoreRule{pattern: "**/keep.map", negate: true}, ok: true},
+		{line: `\#file`, want: ignoreRule{pattern: "**/#file"}, ok: true},
+		{line: "cache/**", want: ignoreRule{pattern: "cache/**/*"}, ok: true},
+	}
+
+	for _, tt := range tests {
+		rule, ok := parseIgnoreRule(tt.line)
+		assert.Equal(t, tt.ok, ok, tt.line)
+		if tt.ok {
+			assert.Equal(t, tt.want, rule, tt.line)
+		}
+	}
+}
+
+func TestWalkUploadFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		IgnoreFileName:              "*.map\n!keep.map\n.git/\n.DS_Store\n",
+		"index.html":                "",
+		"app.js.map":                "",
+		"keep.map":                  "",
+		".DS_Store":                 "",
+		".git/HEAD":                 "",
+		"assets/app.js":             "",
+		"assets/vendor.js.map":      "",
+		"assets/" + IgnoreFileName:  "*.js\n!app.js\n",
+		"assets/lib.js":             "",
+		"cache/data/" + ".DS_Store": "",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	walk := func(uploadOpts UploadOptions) ([]string, skipCount) {
+		var got []string
+		skipped, err := walkUploadFiles(dir, uploadOpts, func(path string, info os.FileInfo) error {
+			rel, _ := filepath.Rel(dir, path)
+			got = append(got, filepath.ToSlash(rel))
+			return nil
+		})
+		assert.NoError(t, err)
+		return got, skipped
+	}
+
+	got, skipped := walk(UploadOptions{})
+	assert.Equal(t, []string{"assets/app.js", "index.html", "keep.map"}, got)
+	assert.Equal(t, skipCount{files: 7, dirs: 1}, skipped)
+
+	got, skipped = walk(UploadOptions{NoIgnore: true})
+	assert.Len(t, got, len(files))
+	assert.Equal(t, skipCount{}, skipped)
+
+	// 只上传清单中的文件，路径相对于上传目录，清单之外的文件不计入跳过
+	got, skipped = walk(UploadOptions{Only: map[string]bool{
+		"index.html":    true,
+		"assets/app.js": true,
+		"app.js.map":    true,
+	}})
+	assert.Equal(t, []string{"assets/app.js", "index.html"}, got)
+	assert.Equal(t, skipCount{files: 7, dirs: 1}, skipped)
+
+	// 清单中的路径都不匹配时返回错误
+	_, err := walkUploadFiles(dir, UploadOptions{Only: map[string]bool{filepath.Join(dir, "index.html"): true}}, func(path string, info os.FileInfo) error {
+		t.Errorf("不应上传 %s", path)
+		return nil
+	})
+	assert.Error(t, err)
+}
+
+func TestWalkUploadFilesSymlinks(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "src")
+	for _, name := range []string{"src/sub", "ext"} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(root, name), 0o755))
+	}
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), nil, 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "ext", "e.txt"), nil, 0o644))
+	assert.NoError(t, os.Symlink("a.txt", filepath.Join(dir, "link.txt")))
+	assert.NoError(t, os.Symlink(filepath.Join(root, "ext"), filepath.Join(dir, "sub", "ext")))
+	assert.NoError(t, os.Symlink("..", filepath.Join(dir, "sub", "loop")))
+
+	walk := func(mode SymlinkMode) ([]string, skipCount) {
+		var got []string
+		skipped, err := walkUploadFiles(dir, UploadOptions{Symlinks: mode}, func(path string, info os.FileInfo) error {
+			rel, _ := filepath.Rel(dir, path)
+			got = append(got, filepath.ToSlash(rel))
+			return nil
+		})
+		assert.NoError(t, err)
+		return got, skipped
+	}
+
+	got, skipped := walk(SymlinkFollow)
+	assert.Equal(t, []string{"a.txt", "link.txt", "sub/ext/e.txt"}, got)
+	assert.Equal(t, skipCount{links: 1}, skipped)
+
+	got, skipped = walk(SymlinkSkip)
+	assert.Equal(t, []string{"a.txt"}, got)
+	assert.Equal(t, skipCount{links: 3}, skipped)
+
+	got, skipped = walk(SymlinkPreserve)
+	assert.Equal(t, []string{"a.txt", "link.txt", "sub/ext", "sub/loop"}, got)
+	assert.Equal(t, skipCount{}, skipped)
+}
+
+func TestCheckSymlinkParents(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "real"), 0o755))
+	assert.NoError(t, os.Symlink(t.TempDir(), filepath.Join(dir, "link")))
+
+	assert.NoError(t, checkSymlinkParents(dir, filepath.Join(dir, "file")))
+	assert.NoError(t, checkSymlinkParents(dir, filepath.Join(dir, "real", "new", "file")))
+	assert.Error(t, checkSymlinkParents(dir, filepath.Join(dir, "link", "file")))
+}
+
+func TestLinkQueue(t *testing.T) {
+	dir := t.TempDir()
+	outside := t.TempDir()
+
+	q := &linkQueue{}
+	q.add(pendingLink{key: "a/b", path: filepath.Join(dir, "a", "b"), target: outside})
+	q.add(pendingLink{key: "a", path: filepath.Join(dir, "a"), target: outside})
+
+	links := q.sorted()
+	assert.Equal(t, "a", links[0].key)
+	assert.Equal(t, "a/b", links[1].key)
+
+	// 上级目录的链接先创建，其下的链接被拒绝，不会在目录之外创建
+	assert.NoError(t, links[0].create(dir))
+	assert.Error(t, links[1].create(dir))
+	_, err := os.Lstat(filepath.Join(outside, "b"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestParseChecksum(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    minio.ChecksumType
+		wantErr bool
+	}{
+		{name: "", want: minio.ChecksumNone},
+		{name: "sha256", want: minio.ChecksumSHA256},
+		{name: "CRC32C", want: minio.ChecksumFullObjectCRC32C},
+		{name: "crc64nvme", want: minio.ChecksumCRC64NVME},
+		{name: "md5", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseChecksum(tt.name)
+		if tt.wantErr {
+			assert.Error(t, err, tt.name)
+		} else {
+			assert.NoError(t, err, tt.name)
+			assert.Equal(t, tt.want, got, tt.name)
+		}
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file.txt")
+	assert.NoError(t, os.WriteFile(path, []byte("hello"), 0o644))
+
+	c := &Client{}
+	info := minio.ObjectInfo{
+		Size:              5,
+		ChecksumSHA256:    "LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ=",
+		ChecksumCRC64NVME: "invalid=",
+	}
+	assert.NoError(t, c.verifyChecksum("bucket", "file.txt", info, path, minio.ChecksumSHA256))
+	assert.Error(t, c.verifyChecksum("bucket", "file.txt", info, path, minio.ChecksumCRC64NVME))
+}
+
+func TestChecksumWriter(t *testing.T) {
+	sum := func(s string) string {
+		return minio.ChecksumSHA256.ChecksumBytes([]byte(s)).Encoded()
+	}
+	sections := []checksumSection{
+		{part: 1, size: 3, want: sum("hel")},
+		{part: 2, size: 2, want: sum("lo")},
+	}
+
+	// 写入的数据块跨越分片边界时按分片分别计算
+	w := newChecksumWriter(minio.ChecksumSHA256, sections)
+	for _, chunk := range []string{"h", "ell", "o"} {
+		_, err := w.Write([]byte(chunk))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, w.verify())
+
+	w = newChecksumWriter(minio.ChecksumSHA256, sections)
+	_, _ = w.Write([]byte("help!"))
+	assert.ErrorContains(t, w.verify(), "分片 2")
+
+	w = newChecksumWriter(minio.ChecksumSHA256, sections)
+	_, _ = w.Write([]byte("hel"))
+	assert.Error(t, w.verify())
+	_, err := w.Write([]byte("lo!"))
+	assert.Error(t, err)
+
+	// 空对象
+	w = newChecksumWriter(minio.ChecksumSHA256, []checksumSection{{want: sum("")}})
+	assert.NoError(t, w.verify())
+}
+
+func TestUploadOptionsChecksumWithResume(t *testing.T) {
+	opts := UploadOptions{Checksum: minio.ChecksumSHA256, Resume: true}
+	assert.Error(t, opts.Validate())
+}
+
+func TestRateLimiter(t *testing.T) {
+	assert.Nil(t, newRateLimiter(0))
+
+	limiter := newRateLimiter(10 << 20)
+	start := time.Now()
+	limiter.last = start
+
+	// 令牌桶初始为满，容量内的突发不需要等待
+	assert.Equal(t, time.Duration(0), limiter.reserve(start, int(limiter.burst)))
+
+	// 透支 10MiB 需要等待 1 秒
+	assert.Equal(t, time.Second, limiter.reserve(start, 10<<20))
+
+	// 多个调用者共享透支，后来者需要等待之前的透支补足
+	assert.Equal(t, 1500*time.Millisecond, limiter.reserve(start.Add(time.Second), 15<<20))
+
+	// 空闲很久后令牌不会超过桶容量
+	limiter.reserve(start.Add(time.Hour), 0)
+	assert.Equal(t, limiter.burst, limiter.tokens)
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{Retries: 5, MaxWait: 5 * time.Second}
+
+	// 等待时间按指数增长，随机部分在一半到全部之间
+	assert.Equal(t, 500*time.Millisecond, policy.backoff(1, 0))
+	assert.Equal(t, time.Second, policy.backoff(2, 0))
+	assert.Equal(t, 3*time.Second, policy.backoff(3, 0.5))
+
+	// 不超过最长等待时间
+	assert.Equal(t, 2500*time.Millisecond, policy.backoff(10, 0))
+	assert.Equal(t, 2500*time.Millisecond, policy.backoff(100, 0))
+	assert.Less(t, policy.backoff(100, 0.999), 5*time.Second)
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "slow down", err: minio.ErrorResponse{Code: "SlowDown", StatusCode: http.StatusServiceUnavailable}, want: true},
+		{name: "wrapped internal error", err: fmt.Errorf("上传文件失败: %w", minio.ErrorResponse{Code: "InternalError", StatusCode: http.StatusInternalServerError}), want: true},
+		{name: "bad gateway without code", err: minio.ErrorResponse{StatusCode: http.StatusBadGateway}, want: true},
+		{name: "too many requests", err: minio.ErrorResponse{StatusCode: http.StatusTooManyRequests}, want: true},
+		{name: "access denied", err: minio.ErrorResponse{Code: "AccessDenied", StatusCode: http.StatusForbidden}, want: false},
+		{name: "no such key", err: minio.ErrorResponse{Code: "NoSuchKey", StatusCode: http.StatusNotFound}, want: false},
+		{name: "connection reset", err: fmt.Errorf("下载文件失败: %w", syscall.ECONNRESET), want: true},
+		{name: "unexpected eof", err: io.ErrUnexpectedEOF, want: true},
+		{name: "network error", err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, want: true},
+		{name: "canceled", err: fmt.Errorf("上传文件失败: %w", context.Canceled), want: false},
+		{name: "local error", err: os.ErrNotExist, want: false},
+		{name: "open local file", err: fmt.Errorf("打开文件失败: %w", &os.PathError{Op: "open", Path: "a", Err: syscall.ENOENT}), want: false},
+		{name: "http request error", err: &url.Error{Op: "Put", URL: "http://127.0.0.1", Err: io.EOF}, want: true},
+		{name: "http request timeout", err: &url.Error{Op: "Put", URL: "http://127.0.0.1", Err: os.ErrDeadlineExceeded}, want: true},
+		{name: "certificate error", err: fmt.Errorf("上传文件失败: %w", &url.Error{Op: "Put", URL: "https://127.0.0.1", Err: x509.UnknownAuthorityError{}}), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isRetryable(tt.err))
+		})
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	retryable := minio.ErrorResponse{Code: "SlowDown", StatusCode: http.StatusServiceUnavailable}
+	c := &Client{ctx: context.Background(), retry: RetryPolicy{Retries: 2, MaxWait: time.Millisecond}}
+
+	t.Run("retries until success and restarts progress", func(t *testing.T) {
+		display := newMultiProgress("上传", 1, ProgressPlain)
+		slot := display.slot(0)
+		display.addFile(100)
+
+		attempts := 0
+		err := c.withRetry("bucket/key", slot.tracker, func(newProgress progressFunc) error {
+			attempts++
+			progress := newProgress(100)
+			_, _ = progress.Read(make([]byte, 60))
+			if attempts < 3 {
+				return retryable
+			}
+			_, _ = progress.Read(make([]byte, 40))
+			return nil
+		})
+		display.stop()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, attempts)
+		assert.Equal(t, int64(100), display.doneBytes)
+	})
+
+	t.Run("gives up after retries", func(t *testing.T) {
+		attempts := 0
+		err := c.withRetry("bucket/key", nil, func(progressFunc) error {
+			attempts++
+			return retryable
+		})
+		assert.ErrorIs(t, err, retryable)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("does not retry permanent errors", func(t *testing.T) {
+		attempts := 0
+		err := c.withRetry("bucket/key", nil, func(progressFunc) error {
+			attempts++
+			return minio.ErrorResponse{Code: "AccessDenied", StatusCode: http.StatusForbidden}
+		})
+		assert.Error(t, err)
+		assert.Equal(t, 1, attempts)
+	})
+}
+
+func TestParseProgressMode(t *testing.T) {
+	for _, s := range []string{"auto", "bar", "plain", "json", "none"} {
+		mode, err := ParseProgressMode(s)
+		assert.NoError(t, err)
+		assert.Equal(t, ProgressMode(s), mode)
+	}
+
+	_, err := ParseProgressMode("fancy")
+	assert.Error(t, err)
+}
+
+func TestJSONProgressEvents(t *testing.T) {
+	var buf bytes.Buffer
+	eventOutput = &buf
+	defer func() { eventOutput = os.Stderr }()
+
+	c := &Client{ctx: context.Background(), progress: ProgressJSON, retry: RetryPolicy{Retries: 1, MaxWait: time.Millisecond}}
+	attempts := 0
+	err := c.withRetry("bucket/key", c.fileProgress("key"), func(newProgress progressFunc) error {
+		attempts++
+		progress := newProgress(100)
+		_, _ = progress.Read(make([]byte, 60))
+		if attempts == 1 {
+			return minio.ErrorResponse{Code: "SlowDown", StatusCode: http.StatusServiceUnavailable}
+		}
+		_, _ = progress.Read(make([]byte, 60))
+		return nil
+	})
+	assert.NoError(t, err)
+
+	var events []progressEvent
+	decoder := json.NewDecoder(&buf)
+	for decoder.More() {
+		var e progressEvent
+		assert.NoError(t, decoder.Decode(&e))
+		events = append(events, e)
+	}
+
+	if assert.Len(t, events, 3) {
+		assert.Equal(t, progressEvent{Event: "start", Key: "key", Total: 100}, events[0])
+		assert.Equal(t, "start", events[1].Event)
+		assert.Equal(t, "done", events[2].Event)
+		assert.Equal(t, "key", events[2].Key)
+		assert.Equal(t, int64(100), events[2].Bytes)
+		assert.Equal(t, int64(100), events[2].Total)
+	}
+
+	buf.Reset()
+	err = c.withRetry("bucket/key", c.fileProgress("key"), func(newProgress progressFunc) error {
+		newProgress(-1)
+		return errors.New("拒绝访问")
+	})
+	assert.Error(t, err)
+	assert.JSONEq(t, `{"event":"error","key":"key","bytes":0,"total":-1,"rate":0,"error":"拒绝访问"}`,
+		strings.Split(strings.TrimSpace(buf.String()), "\n")[1])
+
+	// 创建进度跟踪器之前就失败时同样输出 start 和 error 事件
+	buf.Reset()
+	err = c.withRetry("bucket/missing", c.fileProgress("missing"), func(newProgress progressFunc) error {
+		return errors.New("对象不存在")
+	})
+	assert.Error(t, err)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if assert.Len(t, lines, 2) {
+		assert.JSONEq(t, `{"event":"start","key":"missing","bytes":0,"total":-1,"rate":0}`, lines[0])
+		assert.JSONEq(t, `{"event":"error","key":"missing","bytes":0,"total":-1,"rate":0,"error":"对象不存在"}`, lines[1])
+	}
+}
+
+func TestFileAttrsMetadata(t *testing.T) {
+	attrs := fileAttrs{
+		mtime:    time.Unix(1577934245, 123456789),
+		mode:     0o751 | os.ModeSetuid,
+		uid:      1234,
+		gid:      5678,
+		hasMtime: true,
+		hasMode:  true,
+		hasOwner: true,
+	}
+
+	meta := attrs.metadata()
+	assert.Equal(t, "1577934245.123456789", meta[mtimeHeader])
+	assert.Equal(t, "0104751", meta[modeHeader])
+	assert.Equal(t, "gid:5678/mode:35305/mtime:1577934245/uid:1234", meta[s3cmdAttrsHeader])
+
+	header := make(http.Header)
+	for k, v := range meta {
+		header.Set(k, v)
+	}
+	got := objectAttrs(minio.ObjectInfo{Metadata: header})
+	assert.True(t, got.mtime.Equal(attrs.mtime))
+	attrs.mtime = got.mtime
+	assert.Equal(t, attrs, got)
+
+	t.Run("s3cmd attrs only", func(t *testing.T) {
+		header := http.Header{}
+		header.Set(s3cmdAttrsHeader, "atime:1/ctime:2/gid:20/gname:staff/mode:33188/mtime:1700000000/uid:501/uname:me")
+		got := objectAttrs(minio.ObjectInfo{Metadata: header})
+		assert.True(t, got.hasMtime && got.hasMode && got.hasOwner)
+		assert.Equal(t, int64(1700000000), got.mtime.Unix())
+		assert.Equal(t, os.FileMode(0o644), got.mode)
+		assert.Equal(t, 501, got.uid)
+		assert.Equal(t, 20, got.gid)
+	})
+
+	t.Run("no attrs", func(t *testing.T) {
+		got := objectAttrs(minio.ObjectInfo{Metadata: http.Header{}})
+		assert.Equal(t, fileAttrs{}, got)
+	})
+}
+
+func TestParseMtime(t *testing.T) {
+	tests := []struct {
+		input string
+		want  time.Time
+		ok    bool
+	}{
+		{input: "1700000000", want: time.Unix(1700000000, 0), ok: true},
+		{input: "1700000000.5", want: time.Unix(1700000000, 500000000), ok: true},
+		{input: "1700000000.1234567891", want: time.Unix(1700000000, 123456789), ok: true},
+		{input: "", ok: false},
+		{input: "2023-11-14T22:13:20Z", ok: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseMtime(tt.input)
+		assert.Equal(t, tt.ok, ok, tt.input)
+		if tt.ok {
+			assert.True(t, tt.want.Equal(got), tt.input)
+		}
+	}
+}
+
+func TestContentType(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	c := &Client{contentTypes: extensionTypes(map[string]string{"MD": "text/x-custom", ".wasm": "application/wasm"})}
+
+	tests := []struct {
+		name     string
+		filename string
+		opts     UploadOptions
+		head     []byte
+		want     string
+	}{
+		{name: "known extension", filename: "index.html", head: png, want: "text/html; charset=utf-8"},
+		{name: "profile override", filename: "README.MD", want: "text/x-custom"},
+		{name: "profile extension", filename: "app.wasm", want: "application/wasm"},
+		{name: "sniff without extension", filename: "image", head: png, want: "image/png"},
+		{name: "sniff unknown extension", filename: "page.weird", head: []byte("<html><body></body></html>"), want: "text/html; charset=utf-8"},
+		{name: "unknown content", filename: "data", head: []byte{0, 1, 2, 3}, want: "application/octet-stream"},
+		{name: "forced", filename: "index.html", opts: UploadOptions{ContentType: "text/plain"}, want: "text/plain"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, c.contentType(tt.filename, tt.opts, bytes.NewReader(tt.head)))
+		})
+	}
+}
+
+func TestParseHeader(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Header
+		wantErr bool
+	}{
+		{input: "Cache-Control: no-cache", want: Header{Name: "Cache-Control", Value: "no-cache"}},
+		{input: "cache-control=max-age=60", want: Header{Name: "Cache-Control", Value: "max-age=60"}},
+		{input: "Expires: Tue, 01 Jan 2030 00:00:00 GMT", want: Header{Name: "Expires", Value: "Tue, 01 Jan 2030 00:00:00 GMT"}},
+		{input: "x-amz-meta-owner: ops", want: Header{Name: "X-Amz-Meta-Owner", Value: "ops"}},
+		{input: "Expires: tomorrow", wantErr: true},
+		{input: "X-Foo: 1", wantErr: true},
+		{input: "no-separator", wantErr: true},
+		{input: "x-amz-meta-: 1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseHeader(tt.input)
+		if tt.wantErr {
+			assert.Error(t, err, tt.input)
+			continue
+		}
+		assert.NoError(t, err, tt.input)
+		assert.Equal(t, tt.want, got, tt.input)
+	}
+
+	header, err := ParseMetadata("owner=ops=1")
+	assert.NoError(t, err)
+	assert.Equal(t, Header{Name: "X-Amz-Meta-Owner", Value: "ops=1"}, header)
+
+	_, err = ParseMetadata("owner")
+	assert.Error(t, err)
+}
+
+func TestHeaderRules(t *testing.T) {
+	rules, err := parseHeaderRules(strings.NewReader(`
+# 静态站点
+*.html     Cache-Control: no-cache
+assets/**  Cache-Control: public, max-age=31536000
+/robots.txt	Content-Type: text/plain
+`))
+	assert.NoError(t, err)
+
+	noCache := Header{Name: "Cache-Control", Value: "no-cache"}
+	maxAge := Header{Name: "Cache-Control", Value: "public, max-age=31536000"}
+	assert.Equal(t, []Header{noCache}, rules.Match("index.html"))
+	assert.Equal(t, []Header{noCache}, rules.Match("docs/guide/index.html"))
+	assert.Equal(t, []Header{maxAge}, rules.Match("assets/js/app.js"))
+	assert.Equal(t, []Header{noCache, maxAge}, rules.Match("assets/demo.html"))
+	assert.Len(t, rules.Match("docs/robots.txt"), 0)
+	assert.Len(t, rules.Match("robots.txt"), 1)
+
+	// 命令行指定的头在规则之后，应用时优先
+	explicit := Header{Name: "Cache-Control", Value: "max-age=60"}
+	uploadOpts := UploadOptions{Headers: []Header{explicit}, HeaderRules: rules}
+	fileOpts := uploadOpts.forFile("site", filepath.Join("site", "assets", "a.html"))
+	assert.Nil(t, fileOpts.HeaderRules)
+	assert.Equal(t, []Header{noCache, maxAge, explicit}, fileOpts.Headers)
+
+	var opts minio.PutObjectOptions
+	applyHeaders(&opts, append(fileOpts.Headers, Header{Name: "X-Amz-Meta-Owner", Value: "ops"}))
+	assert.Equal(t, "max-age=60", opts.CacheControl)
+	assert.Equal(t, map[string]string{"X-Amz-Meta-Owner": "ops"}, opts.UserMetadata)
+
+	for _, content := range []string{"*.html\n", "*.html X-Foo: 1\n", "[ Cache-Control: no-cache\n"} {
+		_, err := parseHeaderRules(strings.NewReader(content))
+		assert.Error(t, err, content)
+	}
+}
+
+func TestUploadStreamObjectName(t *testing.T) {
+	c := &Client{ctx: context.Background()}
+	for _, name := range []string{"", "dir/"} {
+		assert.Error(t, c.UploadStream("bucket", name, strings.NewReader("data"), UploadOptions{}), name)
+	}
+}
+
+func TestStreamOptions(t *testing.T) {
+	c := &Client{}
+
+	opts := c.streamOptions("backup.tar", UploadOptions{}, nil)
+	assert.Equal(t, uint64(DefaultStreamPartSize), opts.PartSize)
+	assert.False(t, opts.ConcurrentStreamParts)
+
+	opts = c.streamOptions("backup.tar", UploadOptions{PartSize: 64 << 20, PartJobs: 4}, nil)
+	assert.Equal(t, uint64(64<<20), opts.PartSize)
+	assert.Equal(t, uint(4), opts.NumThreads)
+	assert.True(t, opts.ConcurrentStreamParts)
+
+	// 扩展名未知时按数据流开头的内容识别
+	opts = c.streamOptions("data", UploadOptions{}, []byte("%PDF-1.7\n"))
+	assert.Equal(t, "application/pdf", opts.ContentType)
+}
+
+func TestCatObject(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "5")
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		fmt.Fprint(w, "hello")
+	}))
+	defer server.Close()
+	c := newTestClient(t, server)
+
+	// 标准输出上不能有进度或提示信息
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	assert.NoError(t, err)
+	os.Stdout = w
+
+	var buf bytes.Buffer
+	err = c.CatObject("bucket", "file.txt", &buf)
+	os.Stdout = stdout
+	assert.NoError(t, w.Close())
+	printed, _ := io.ReadAll(r)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", buf.String())
+	assert.Empty(t, printed)
+
+	assert.Error(t, c.CatObject("bucket", "dir/", &buf))
+}
+
+func TestCopyRanges(t *testing.T) {
+	tests := []struct {
+		name  string
+		size  int64
+		parts int
+		last  int64
+	}{
+		{name: "just over 5GiB", size: MaxPartSize + 1, parts: 11, last: 1},
+		{name: "exact multiple", size: 6 << 30, parts: 12, last: CopyPartSize},
+		{name: "short last part", size: 6<<30 + 100, parts: 13, last: 100},
+		{name: "part size grows to stay within 10000 parts", size: 10000*CopyPartSize + 1, parts: 10000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranges := copyRanges(tt.size)
+			assert.Len(t, ranges, tt.parts)
+
+			// 各范围首尾相接，覆盖整个对象
+			var next int64
+			for _, r := range ranges {
+				assert.Equal(t, next, r.start)
+				next = r.end + 1
+			}
+			assert.Equal(t, tt.size, next)
+			if tt.last > 0 {
+				last := ranges[len(ranges)-1]
+				assert.Equal(t, tt.last, last.end-last.start+1)
+			}
+		})
+	}
+}
+
+func TestCopyDest(t *testing.T) {
+	src := minio.ObjectInfo{
+		ContentType:  "image/png",
+		UserMetadata: map[string]string{"Owner": "alice", "Team": "web"},
+	}
+
+	// 没有覆盖值时由服务端原样复制
+	dst := copyDest(src, "bucket", "b.png", CopyOptions{})
+	assert.False(t, dst.ReplaceMetadata)
+
+	// 只覆盖用户元数据时保留源对象的 Content-Type 和其余元数据
+	dst = copyDest(src, "bucket", "b.png", CopyOptions{Metadata: map[string]string{"Owner": "bob"}})
+	assert.True(t, dst.ReplaceMetadata)
+	assert.Equal(t, "image/png", dst.ContentType)
+	assert.Equal(t, map[string]string{"Owner": "bob", "Team": "web"}, dst.UserMetadata)
+	assert.Equal(t, "alice", src.UserMetadata["Owner"])
+
+	dst = copyDest(src, "bucket", "b.png", CopyOptions{ContentType: "image/webp"})
+	assert.Equal(t, "image/webp", dst.ContentType)
+	assert.Equal(t, map[string]string{"Owner": "alice", "Team": "web"}, dst.UserMetadata)
+}
+
+func TestCopyObjectMultipartKeepsContentType(t *testing.T) {
+	var (
+		initHeader http.Header
+		parts      int
+		completed  bool
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case r.Method == http.MethodHead:
+			w.Header().Set("Content-Length", strconv.FormatInt(6<<30, 10))
+			w.Header().Set("Content-Type", "video/mp4")
+			w.Header().Set("ETag", `"src-etag"`)
+			w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+			w.Header().Set("X-Amz-Meta-Owner", "alice")
+		case r.Method == http.MethodPost && query.Has("uploads"):
+			initHeader = r.Header.Clone()
+			fmt.Fprint(w, `<InitiateMultipartUploadResult><Bucket>bucket</Bucket><Key>big.mp4</Key><UploadId>upload-id</UploadId></InitiateMultipartUploadResult>`)
+		case r.Method == http.MethodPut && query.Has("partNumber"):
+			parts++
+			fmt.Fprintf(w, `<CopyPartResult><ETag>"part-%d"</ETag><LastModified>2024-01-01T00:00:00Z</LastModified></CopyPartResult>`, parts)
+		case r.Method == http.MethodPost && query.Has("uploadId"):
+			completed = true
+			fmt.Fprint(w, `<CompleteMultipartUploadResult><Bucket>bucket</Bucket><Key>big.mp4</Key><ETag>"dst-etag"</ETag></CompleteMultipartUploadResult>`)
+		default:
+			w.WriteHeader(http.StatusNotImplemented)
+		}
+	}))
+	defer server.Close()
+	c := newTestClient(t, server)
+
+	// ComposeObject 不会保留 Content-Type，超过 5GiB 的对象自行分片复制，创建分片上传时带上源对象的值
+	assert.NoError(t, c.CopyObject("bucket", "src.mp4", "bucket", "big.mp4", CopyOptions{Metadata: map[string]string{"Team": "web"}}))
+	assert.Equal(t, "video/mp4", initHeader.Get("Content-Type"))
+	assert.Equal(t, "alice", initHeader.Get("X-Amz-Meta-Owner"))
+	assert.Equal(t, "web", initHeader.Get("X-Amz-Meta-Team"))
+	assert.Equal(t, 12, parts)
+	assert.True(t, completed)
+}
+
+func TestJoinObjectPath(t *testing.T) {
+	tests := []struct {
+		prefix, rel, want string
+	}{
+		{prefix: "", rel: "a/b.txt", want: "a/b.txt"},
+		{prefix: "backup", rel: "a/b.txt", want: "backup/a/b.txt"},
+		{prefix: "backup/", rel: "a/b.txt", want: "backup/a/b.txt"},
+		{prefix: "backup", rel: "a/", want: "backup/a/"},
+		{prefix: "", rel: "a/", want: "a/"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, joinObjectPath(tt.prefix, tt.rel), tt.prefix+"|"+tt.rel)
+	}
+}
+
+func TestMoveDirectoryFolderMarkers(t *testing.T) {
+	keys := []string{"old/", "old/a.txt", "old/sub/"}
+	var copied, deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.URL.Path, "/bucket/")
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, `<ListBucketResult><Name>bucket</Name><IsTruncated>false</IsTruncated>`)
+			for _, key := range keys {
+				fmt.Fprintf(w, `<Contents><Key>%s</Key><Size>0</Size></Contents>`, key)
+			}
+			fmt.Fprint(w, `</ListBucketResult>`)
+		case http.MethodHead:
+			w.Header().Set("Content-Length", "0")
+			w.Header().Set("ETag", `"d41d8cd98f00b204e9800998ecf8427e"`)
+			w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		case http.MethodPut:
+			copied = append(copied, key)
+			fmt.Fprint(w, `<CopyObjectResult><ETag>"d41d8cd98f00b204e9800998ecf8427e"</ETag><LastModified>2024-01-01T00:00:00Z</LastModified></CopyObjectResult>`)
+		case http.MethodDelete:
+			deleted = append(deleted, key)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+	c := newTestClient(t, server)
+
+	// 目录标记与其他对象一样复制、校验后删除
+	result, err := c.MoveDirectory("bucket", "old/", "bucket", "new/", CopyOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, MoveResult{Moved: 3}, result)
+	assert.Equal(t, []string{"new/", "new/a.txt", "new/sub/"}, copied)
+	assert.Equal(t, keys, deleted)
+}
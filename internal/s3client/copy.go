@@ -0,0 +1,172 @@
+package s3client
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// CopyPartSize 服务端分片复制时使用的分片大小
+const CopyPartSize = 512 << 20
+
+// CopyOptions 复制选项
+type CopyOptions struct {
+	ContentType string            // 覆盖目标对象的 Content-Type，为空时保留源对象的值
+	Metadata    map[string]string // 覆盖或追加的用户元数据，其余元数据从源对象保留
+}
+
+// CopyObject 在服务端复制对象。不超过 5GiB 的对象使用单次 CopyObject，
+// 更大的对象使用分片复制
+func (c *Client) CopyObject(srcBucket, srcObject, dstBucket, dstObject string, copyOpts CopyOptions) error {
+	if c.dryRun {
+		_, err := c.planCopy(c, srcBucket, srcObject, dstBucket, dstObject)
+		return err
+	}
+	fmt.Printf("复制 %s/%s 到 %s/%s...\n", srcBucket, srcObject, dstBucket, dstObject)
+	_, err := c.copyObject(srcBucket, srcObject, dstBucket, dstObject, copyOpts)
+	return err
+}
+
+// copyObject 在服务端复制对象，返回复制前源对象的信息，暂时性的错误按重试策略重试
+func (c *Client) copyObject(srcBucket, srcObject, dstBucket, dstObject string, copyOpts CopyOptions) (minio.ObjectInfo, error) {
+	var src minio.ObjectInfo
+	err := c.withRetry(srcBucket+"/"+srcObject, nil, func(progressFunc) error {
+		var err error
+		src, err = c.copyObjectOnce(srcBucket, srcObject, dstBucket, dstObject, copyOpts)
+		return err
+	})
+	return src, err
+}
+
+// copyObjectOnce 执行一次服务端复制
+func (c *Client) copyObjectOnce(srcBucket, srcObject, dstBucket, dstObject string, copyOpts CopyOptions) (minio.ObjectInfo, error) {
+	src, err := c.client.StatObject(c.ctx, srcBucket, srcObject, minio.StatObjectOptions{})
+	if err != nil {
+		return src, fmt.Errorf("获取源对象信息失败: %w", err)
+	}
+
+	if src.Size > MaxPartSize {
+		contentType, metadata := copyMetadata(src, copyOpts)
+		return src, c.copyObjectMultipart(srcBucket, srcObject, src, dstBucket, dstObject, contentType, metadata)
+	}
+
+	_, err = c.client.CopyObject(c.ctx, copyDest(src, dstBucket, dstObject, copyOpts), minio.CopySrcOptions{
+		Bucket:    srcBucket,
+		Object:    srcObject,
+		MatchETag: src.ETag,
+	})
+	if err != nil {
+		return src, fmt.Errorf("复制对象失败: %w", err)
+	}
+	return src, nil
+}
+
+// copyMetadata 合并源对象的元数据与覆盖值，返回目标对象的 Content-Type 和用户元数据
+func copyMetadata(src minio.ObjectInfo, copyOpts CopyOptions) (string, map[string]string) {
+	metadata := maps.Clone(src.UserMetadata)
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	maps.Copy(metadata, copyOpts.Metadata)
+	contentType := src.ContentType
+	if copyOpts.ContentType != "" {
+		contentType = copyOpts.ContentType
+	}
+	return contentType, metadata
+}
+
+// copyDest 返回单次复制的目标选项。有覆盖值时替换全部元数据，Content-Type 和其余元数据
+// 沿用源对象的值，否则由服务端原样复制
+func copyDest(src minio.ObjectInfo, dstBucket, dstObject string, copyOpts CopyOptions) minio.CopyDestOptions {
+	dst := minio.CopyDestOptions{
+		Bucket: dstBucket,
+		Object: dstObject,
+	}
+	if copyOpts.ContentType != "" || len(copyOpts.Metadata) > 0 {
+		dst.ReplaceMetadata = true
+		dst.ContentType, dst.UserMetadata = copyMetadata(src, copyOpts)
+	}
+	return dst
+}
+
+// copyObjectMultipart 将超过 5GiB 的对象按分片在服务端复制，
+// 与 ComposeObject 的做法相同，但会保留 Content-Type
+func (c *Client) copyObjectMultipart(srcBucket, srcObject string, src minio.ObjectInfo, dstBucket, dstObject, contentType string, metadata map[string]string) error {
+	core := minio.Core{Client: c.client}
+	uploadID, err := core.NewMultipartUpload(c.ctx, dstBucket, dstObject, minio.PutObjectOptions{
+		ContentType:  contentType,
+		UserMetadata: metadata,
+	})
+	if err != nil {
+		return fmt.Errorf("创建分片复制失败: %w", err)
+	}
+
+	header := map[string]string{"x-amz-copy-source-if-match": src.ETag}
+
+	parts := []minio.CompletePart{}
+	for i, r := range copyRanges(src.Size) {
+		partNumber := i + 1
+		part, err := core.CopyObjectPart(c.ctx, srcBucket, srcObject, dstBucket, dstObject, uploadID, partNumber, r.start, r.end-r.start+1, header)
+		if err != nil {
+			_ = core.AbortMultipartUpload(c.ctx, dstBucket, dstObject, uploadID)
+			return fmt.Errorf("复制分片 %d 失败: %w", partNumber, err)
+		}
+		parts = append(parts, part)
+	}
+
+	if _, err := core.CompleteMultipartUpload(c.ctx, dstBucket, dstObject, uploadID, parts, minio.PutObjectOptions{}); err != nil {
+		_ = core.AbortMultipartUpload(c.ctx, dstBucket, dstObject, uploadID)
+		return fmt.Errorf("完成分片复制失败: %w", err)
+	}
+	return nil
+}
+
+// copyRanges 将对象拆分为分片复制的字节范围，分片不小于 CopyPartSize，分片数不超过 10000
+func copyRanges(size int64) []byteRange {
+	partSize := max(int64(CopyPartSize), (size+9999)/10000)
+	var ranges []byteRange
+	for offset := int64(0); offset < size; offset += partSize {
+		ranges = append(ranges, byteRange{start: offset, end: min(offset+partSize, size) - 1})
+	}
+	return ranges
+}
+
+// CopyDirectory 递归复制前缀下的所有对象，保持相对路径不变
+func (c *Client) CopyDirectory(srcBucket, srcPrefix, dstBucket, dstPrefix string, copyOpts CopyOptions) error {
+	objects := c.ListObjects(srcBucket, srcPrefix, true, false)
+	for object := range objects {
+		if object.Err != nil {
+			return fmt.Errorf("列出对象失败: %w", object.Err)
+		}
+
+		// 跳过目录标记
+		if strings.HasSuffix(object.Key, "/") {
+			continue
+		}
+
+		dstObject := joinObjectPath(dstPrefix, strings.TrimPrefix(object.Key, srcPrefix))
+		if srcBucket == dstBucket && dstObject == object.Key {
+			continue
+		}
+
+		if err := c.CopyObject(srcBucket, object.Key, dstBucket, dstObject, copyOpts); err != nil {
+			return fmt.Errorf("复制对象 %s 失败: %w", object.Key, err)
+		}
+	}
+	return nil
+}
+
+// joinObjectPath 拼接对象前缀与相对路径，相对路径以 / 结尾时结果保留结尾的 /
+func joinObjectPath(prefix, rel string) string {
+	if prefix == "" {
+		return rel
+	}
+	joined := path.Join(prefix, rel)
+	if strings.HasSuffix(rel, "/") {
+		joined += "/"
+	}
+	return joined
+}
@@ -0,0 +1,202 @@
+package s3client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/minio/minio-go/v7"
+)
+
+const (
+	partFileSuffix = ".s3ctl-part" // 未完成下载的临时文件后缀
+	partETagSuffix = ".etag"       // 记录临时文件对应对象 ETag 的文件后缀
+)
+
+// MinRangeSize 多连接下载时每个范围的最小字节数
+const MinRangeSize = 8 << 20
+
+// DownloadOptions 下载选项
+type DownloadOptions struct {
+	Connections int                // 单个对象的并发连接数，小于等于 1 时使用单连接
+	Jobs        int                // 下载目录时同时下载的对象数，小于等于 1 时逐个下载
+	Filter      *Filter            // 下载目录时按相对路径筛选对象，nil 表示下载全部对象
+	Checksum    minio.ChecksumType // 下载后按对象存储的附加校验和校验，ChecksumNone 表示不校验
+	KeepGoing   bool               // 下载目录时单个对象失败后继续下载其余对象，结束后返回 PartialError
+	Only        map[string]bool    // 下载目录时只下载其中列出的对象名称，nil 表示全部
+	Preserve    bool               // 恢复上传时保存在元数据中的修改时间、权限、属主和属组
+
+	root  string     // 目录下载的本地根目录，写入前检查根目录之下的各级目录不是符号链接
+	links *linkQueue // 非 nil 时不立即创建符号链接，由下载目录的调用方在普通文件写入完成后创建
+}
+
+// useRanges 判断剩余字节数是否值得拆分为多个范围下载
+func (o DownloadOptions) useRanges(remaining int64) bool {
+	return o.Connections > 1 && remaining >= 2*MinRangeSize
+}
+
+// byteRange 闭区间字节范围
+type byteRange struct {
+	start, end int64
+}
+
+// splitRanges 将 [offset, size) 拆分为最多 n 个连续范围，每个范围不小于 MinRangeSize
+func splitRanges(offset, size int64, n int) []byteRange {
+	remaining := size - offset
+	if remaining <= 0 {
+		return nil
+	}
+
+	chunk := max((remaining+int64(n)-1)/int64(n), MinRangeSize)
+	ranges := make([]byteRange, 0, n)
+	for start := offset; start < size; start += chunk {
+		ranges = append(ranges, byteRange{start: start, end: min(start+chunk, size) - 1})
+	}
+	return ranges
+}
+
+// downloadRanges 并发下载对象剩余部分的各个范围，并按偏移写入预分配的临时文件
+func (c *Client) downloadRanges(bucketName, objectName string, objInfo minio.ObjectInfo, part *partFile, connections int, progress progressSink) error {
+	// 多连接写入会在文件中留下空洞，先删除 ETag 记录，避免中断后按文件长度错误续传
+	if err := os.Remove(part.etagPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("删除临时文件失败: %w", err)
+	}
+	if err := part.file.Truncate(objInfo.Size); err != nil {
+		return fmt.Errorf("预分配文件失败: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(c.ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	for _, r := range splitRanges(part.offset, objInfo.Size, connections) {
+		wg.Add(1)
+		go func(r byteRange) {
+			defer wg.Done()
+			if err := c.downloadRange(ctx, bucketName, objectName, objInfo.ETag, r, part.file, progress); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(r)
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+// downloadRange 下载单个范围并写入文件的对应位置
+func (c *Client) downloadRange(ctx context.Context, bucketName, objectName, etag string, r byteRange, file *os.File, progress progressSink) error {
+	opts := minio.GetObjectOptions{}
+	if err := opts.SetRange(r.start, r.end); err != nil {
+		return fmt.Errorf("设置下载范围失败: %w", err)
+	}
+	if err := opts.SetMatchETag(etag); err != nil {
+		return fmt.Errorf("设置 ETag 校验失败: %w", err)
+	}
+
+	object, err := c.client.GetObject(ctx, bucketName, objectName, opts)
+	if err != nil {
+		return fmt.Errorf("获取对象失败: %w", err)
+	}
+	defer object.Close()
+
+	writer := io.NewOffsetWriter(file, r.start)
+	if _, err := io.Copy(writer, io.TeeReader(object, progress)); err != nil {
+		return fmt.Errorf("下载范围 %d-%d 失败: %w", r.start, r.end, err)
+	}
+	return nil
+}
+
+// partFile 未完成下载的临时文件
+type partFile struct {
+	file     *os.File
+	path     string // 临时文件路径
+	etagPath string // ETag 记录文件路径
+	target   string // 下载完成后的目标路径
+	offset   int64  // 已下载的字节数
+	closed   bool
+}
+
+// openPartFile 打开目标路径对应的临时文件。临时文件存在且记录的 ETag 与对象一致时
+// 从已有长度继续写入，否则从头开始下载
+func openPartFile(target string, objInfo minio.ObjectInfo) (*partFile, error) {
+	p := &partFile{
+		path:     target + partFileSuffix,
+		etagPath: target + partFileSuffix + partETagSuffix,
+		target:   target,
+	}
+
+	if info, err := os.Stat(p.path); err == nil {
+		etag, _ := os.ReadFile(p.etagPath)
+		if trimETag(strings.TrimSpace(string(etag))) == trimETag(objInfo.ETag) && info.Size() <= objInfo.Size {
+			p.offset = info.Size()
+		} else if info.Size() > 0 {
+			fmt.Printf("对象 ETag 已变化，重新下载 %s\n", target)
+		}
+	}
+
+	// 记录当前对象的 ETag，供下次续传时校验
+	if err := os.WriteFile(p.etagPath, []byte(objInfo.ETag), 0o644); err != nil {
+		return nil, fmt.Errorf("写入临时文件失败: %w", err)
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY
+	if p.offset == 0 {
+		flags |= os.O_TRUNC
+	}
+	file, err := os.OpenFile(p.path, flags, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("创建文件失败: %w", err)
+	}
+	if _, err := file.Seek(p.offset, 0); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("定位临时文件失败: %w", err)
+	}
+	p.file = file
+	return p, nil
+}
+
+// commit 关闭临时文件并重命名到目标路径
+func (p *partFile) commit() error {
+	if err := p.Close(); err != nil {
+		return fmt.Errorf("写入文件失败: %w", err)
+	}
+	if err := os.Rename(p.path, p.target); err != nil {
+		return fmt.Errorf("重命名文件失败: %w", err)
+	}
+	if err := os.Remove(p.etagPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("删除临时文件失败: %w", err)
+	}
+	return nil
+}
+
+// remove 关闭并删除临时文件及其 ETag 记录
+func (p *partFile) remove() error {
+	_ = p.Close()
+	if err := os.Remove(p.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("删除临时文件失败: %w", err)
+	}
+	if err := os.Remove(p.etagPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("删除临时文件失败: %w", err)
+	}
+	return nil
+}
+
+// Close 关闭临时文件，可重复调用
+func (p *partFile) Close() error {
+	if p.closed {
+		return nil
+	}
+	p.closed = true
+	return p.file.Close()
+}
@@ -0,0 +1,113 @@
+package s3client
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// 演练模式下输出的操作类型
+const (
+	dryRunUpload    = "上传"
+	dryRunDownload  = "下载"
+	dryRunCopy      = "复制"
+	dryRunOverwrite = "覆盖"
+	dryRunDelete    = "删除"
+	dryRunCreate    = "创建"
+)
+
+// SetDryRun 设置演练模式。演练模式下只列出将要执行的上传、覆盖、删除和创建操作及其大小，
+// 仍会读取对象信息用于比较，但不调用任何写接口，也不修改本地文件
+func (c *Client) SetDryRun(dryRun bool) {
+	c.dryRun = dryRun
+}
+
+// planAction 输出演练模式下的一项操作，size 为负数时表示大小未知
+func planAction(action, target string, size int64) {
+	if size < 0 {
+		fmt.Printf("[dry-run] %s %s\n", action, target)
+		return
+	}
+	fmt.Printf("[dry-run] %s %s (%s)\n", action, target, formatBytes(size))
+}
+
+// planWrite 输出将 source 写入 target 的操作，目标已存在时为覆盖
+func planWrite(action string, exists bool, source, target string, size int64) {
+	if exists {
+		action = dryRunOverwrite
+	}
+	planAction(action, source+" -> "+target, size)
+}
+
+// objectExists 判断对象是否存在
+func (c *Client) objectExists(bucketName, objectName string) (bool, error) {
+	_, err := c.client.StatObject(c.ctx, bucketName, objectName, minio.StatObjectOptions{})
+	if err == nil {
+		return true, nil
+	}
+	if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return false, nil
+	}
+	return false, fmt.Errorf("获取对象信息失败: %w", err)
+}
+
+// planUpload 输出上传文件的操作
+func (c *Client) planUpload(bucketName, filePath, objectName string) error {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return fmt.Errorf("获取文件信息失败: %w", err)
+	}
+	if objectName == "" {
+		objectName = filepath.Base(filePath)
+	}
+
+	exists, err := c.objectExists(bucketName, objectName)
+	if err != nil {
+		return err
+	}
+	planWrite(dryRunUpload, exists, filePath, bucketName+"/"+objectName, info.Size())
+	return nil
+}
+
+// planDownload 输出下载对象的操作
+func (c *Client) planDownload(bucketName, objectName, filePath string) error {
+	objInfo, err := c.client.StatObject(c.ctx, bucketName, objectName, minio.StatObjectOptions{})
+	if err != nil {
+		return fmt.Errorf("获取对象信息失败: %w", err)
+	}
+
+	_, err = os.Stat(filePath)
+	planWrite(dryRunDownload, err == nil, bucketName+"/"+objectName, filePath, objInfo.Size)
+	return nil
+}
+
+// planCopy 输出将对象复制到 dst 的操作，返回源对象的信息
+func (c *Client) planCopy(dst *Client, srcBucket, srcObject, dstBucket, dstObject string) (minio.ObjectInfo, error) {
+	src, err := c.client.StatObject(c.ctx, srcBucket, srcObject, minio.StatObjectOptions{})
+	if err != nil {
+		return src, fmt.Errorf("获取源对象信息失败: %w", err)
+	}
+
+	exists, err := dst.objectExists(dstBucket, dstObject)
+	if err != nil {
+		return src, err
+	}
+	planWrite(dryRunCopy, exists, srcBucket+"/"+srcObject, dstBucket+"/"+dstObject, src.Size)
+	return src, nil
+}
+
+// planDelete 输出删除对象的操作，对象不存在时不输出大小
+func (c *Client) planDelete(bucketName, objectName string) error {
+	size := int64(-1)
+	info, err := c.client.StatObject(c.ctx, bucketName, objectName, minio.StatObjectOptions{})
+	switch {
+	case err == nil:
+		size = info.Size
+	case minio.ToErrorResponse(err).Code != "NoSuchKey":
+		return fmt.Errorf("获取对象信息失败: %w", err)
+	}
+	planAction(dryRunDelete, bucketName+"/"+objectName, size)
+	return nil
+}
@@ -0,0 +1,55 @@
+package s3client
+
+import (
+	"fmt"
+)
+
+// Failure 单个文件或对象的失败
+type Failure struct {
+	Name string // 上传时为本地文件路径，下载和删除时为对象名称
+	Err  error
+}
+
+// PartialError 继续执行模式 (KeepGoing) 下处理完所有文件或对象后，
+// 仍有部分失败时返回的错误，记录了每一个失败
+type PartialError struct {
+	Operation string
+	Succeeded int
+	Failures  []Failure
+}
+
+func (e *PartialError) Error() string {
+	return fmt.Sprintf("%s部分失败: 成功 %d 个，失败 %d 个", e.Operation, e.Succeeded, len(e.Failures))
+}
+
+func (e *PartialError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, failure := range e.Failures {
+		errs[i] = failure.Err
+	}
+	return errs
+}
+
+// Names 返回所有失败的文件路径或对象名称，可以写入清单后通过 Only 重新处理
+func (e *PartialError) Names() []string {
+	names := make([]string, len(e.Failures))
+	for i, failure := range e.Failures {
+		names[i] = failure.Name
+	}
+	return names
+}
+
+// partialResult 汇总继续执行模式下的结果，fatal 为遍历或列出对象等无法继续的错误，
+// 有 fatal 时按普通错误返回
+func partialResult(operation string, succeeded int, failures []Failure, fatal []error) error {
+	if len(fatal) > 0 {
+		for _, failure := range failures {
+			fatal = append(fatal, fmt.Errorf("%s %s 失败: %w", operation, failure.Name, failure.Err))
+		}
+		return joinFailures(operation, fatal)
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	return &PartialError{Operation: operation, Succeeded: succeeded, Failures: failures}
+}
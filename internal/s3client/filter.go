@@ -0,0 +1,85 @@
+package s3client
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// Filter 按 doublestar glob 模式筛选相对路径，例如 **/*.log、node_modules/**。
+// 指定了包含模式时只保留至少匹配一个包含模式的路径，匹配任一排除模式的路径总是被排除。
+// nil 表示不筛选
+type Filter struct {
+	include []string
+	exclude []string
+}
+
+// NewFilter 校验模式并创建筛选器，没有任何模式时返回 nil
+func NewFilter(include, exclude []string) (*Filter, error) {
+	if len(include) == 0 && len(exclude) == 0 {
+		return nil, nil
+	}
+
+	for _, pattern := range append(append([]string{}, include...), exclude...) {
+		if !doublestar.ValidatePattern(pattern) {
+			return nil, fmt.Errorf("无效的匹配模式: %s", pattern)
+		}
+	}
+	return &Filter{include: include, exclude: exclude}, nil
+}
+
+// Match 判断相对路径 (以 / 分隔) 是否通过筛选
+func (f *Filter) Match(rel string) bool {
+	if f == nil {
+		return true
+	}
+	if f.excluded(rel) {
+		return false
+	}
+	if len(f.include) == 0 {
+		return true
+	}
+	for _, pattern := range f.include {
+		if doublestar.MatchUnvalidated(pattern, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchKey 判断对象是否通过筛选，对象名称先去掉前缀中最后一个 / 之前的部分得到相对路径。
+// 以 / 结尾的目录只检查排除模式，避免包含模式把目录本身也过滤掉，用于列出对象
+func (f *Filter) MatchKey(prefix, key string) bool {
+	if f == nil {
+		return true
+	}
+	rel := relKey(prefix, key)
+	if rel == "" || strings.HasSuffix(rel, "/") {
+		return !f.excluded(rel)
+	}
+	return f.Match(rel)
+}
+
+// MatchObject 与 MatchKey 相同，但以 / 结尾的目录标记也按包含模式筛选，用于删除等写操作，
+// 避免删除包含模式没有选中的目录标记
+func (f *Filter) MatchObject(prefix, key string) bool {
+	if f == nil {
+		return true
+	}
+	return f.Match(relKey(prefix, key))
+}
+
+// relKey 去掉前缀中最后一个 / 之前的部分，得到对象的相对路径
+func relKey(prefix, key string) string {
+	return strings.TrimPrefix(key, prefix[:strings.LastIndex(prefix, "/")+1])
+}
+
+func (f *Filter) excluded(rel string) bool {
+	for _, pattern := range f.exclude {
+		if doublestar.MatchUnvalidated(pattern, rel) {
+			return true
+		}
+	}
+	return false
+}
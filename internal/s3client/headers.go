@@ -0,0 +1,212 @@
+package s3client
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/minio/minio-go/v7"
+)
+
+// metaPrefix 用户元数据的 HTTP 头前缀
+const metaPrefix = "X-Amz-Meta-"
+
+// supportedHeaders 上传时可以设置的 HTTP 头，此外还可以设置以 X-Amz-Meta- 开头的用户元数据
+var supportedHeaders = map[string]bool{
+	"Cache-Control":       true,
+	"Content-Disposition": true,
+	"Content-Encoding":    true,
+	"Content-Language":    true,
+	"Content-Type":        true,
+	"Expires":             true,
+}
+
+// Header 上传对象时设置的 HTTP 头或用户元数据
+type Header struct {
+	Name  string // 规范化的名称，例如 Cache-Control、X-Amz-Meta-Owner
+	Value string
+}
+
+// ParseHeader 解析 Name: value 或 Name=value 形式的 HTTP 头，以第一个 : 或 = 分隔名称和值
+func ParseHeader(s string) (Header, error) {
+	i := strings.IndexAny(s, ":=")
+	if i <= 0 {
+		return Header{}, fmt.Errorf("无效的 HTTP 头 %q，格式为 Name: value 或 Name=value", s)
+	}
+	return newHeader(s[:i], s[i+1:])
+}
+
+// ParseMetadata 解析 key=value 形式的用户元数据，key 可以省略 x-amz-meta- 前缀
+func ParseMetadata(s string) (Header, error) {
+	key, value, ok := strings.Cut(s, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return Header{}, fmt.Errorf("无效的元数据 %q，格式为 key=value", s)
+	}
+	if !strings.HasPrefix(http.CanonicalHeaderKey(key), metaPrefix) {
+		key = metaPrefix + key
+	}
+	return newHeader(key, value)
+}
+
+// newHeader 校验名称和值并创建 Header
+func newHeader(name, value string) (Header, error) {
+	name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+	value = strings.TrimSpace(value)
+
+	switch {
+	case strings.HasPrefix(name, metaPrefix):
+		if len(name) == len(metaPrefix) {
+			return Header{}, fmt.Errorf("用户元数据的名称不能为空")
+		}
+	case !supportedHeaders[name]:
+		return Header{}, fmt.Errorf("不支持设置 HTTP 头 %s，可选 Cache-Control、Content-Disposition、Content-Encoding、Content-Language、Content-Type、Expires 和 X-Amz-Meta-*", name)
+	case name == "Expires":
+		if _, err := parseExpires(value); err != nil {
+			return Header{}, err
+		}
+	}
+	return Header{Name: name, Value: value}, nil
+}
+
+// parseExpires 解析 HTTP 日期格式或 RFC 3339 格式的过期时间
+func parseExpires(value string) (time.Time, error) {
+	if t, err := http.ParseTime(value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("无效的 Expires %q，使用 HTTP 日期格式 (例如 %s) 或 RFC 3339 格式", value, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC).Format(http.TimeFormat))
+}
+
+// applyHeaders 将 HTTP 头设置到上传选项中，同名的头以靠后的为准
+func applyHeaders(opts *minio.PutObjectOptions, headers []Header) {
+	for _, header := range headers {
+		switch header.Name {
+		case "Cache-Control":
+			opts.CacheControl = header.Value
+		case "Content-Disposition":
+			opts.ContentDisposition = header.Value
+		case "Content-Encoding":
+			opts.ContentEncoding = header.Value
+		case "Content-Language":
+			opts.ContentLanguage = header.Value
+		case "Content-Type":
+			opts.ContentType = header.Value
+		case "Expires":
+			opts.Expires, _ = parseExpires(header.Value)
+		default:
+			if opts.UserMetadata == nil {
+				opts.UserMetadata = make(map[string]string)
+			}
+			opts.UserMetadata[header.Name] = header.Value
+		}
+	}
+}
+
+// headerRule 规则文件中的一条规则
+type headerRule struct {
+	pattern string // doublestar 模式，相对于上传目录
+	header  Header
+}
+
+// HeaderRules 按相对路径为匹配的文件设置 HTTP 头。规则文件每行为一个 glob 模式和一个 HTTP 头，
+// 以空白分隔，例如:
+//
+//	*.html      Cache-Control: no-cache
+//	assets/**   Cache-Control: public, max-age=31536000, immutable
+//
+// 与 .s3ctlignore 相同，不包含 / 的模式匹配任意层级的文件名，包含 / 的模式相对于上传目录。
+// 一个文件可以匹配多条规则，同名的头以靠后的规则为准
+type HeaderRules struct {
+	rules []headerRule
+}
+
+// LoadHeaderRules 读取 HTTP 头规则文件
+func LoadHeaderRules(path string) (*HeaderRules, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取 HTTP 头规则失败: %w", err)
+	}
+	defer file.Close()
+
+	rules, err := parseHeaderRules(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return rules, nil
+}
+
+// parseHeaderRules 解析规则，跳过空行和 # 开头的注释
+func parseHeaderRules(r io.Reader) (*HeaderRules, error) {
+	rules := &HeaderRules{}
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		i := strings.IndexAny(line, " \t")
+		if i < 0 {
+			return nil, fmt.Errorf("第 %d 行缺少 HTTP 头: %s", lineNo, line)
+		}
+		pattern, rest := line[:i], line[i+1:]
+
+		if strings.Contains(pattern, "/") {
+			pattern = strings.TrimPrefix(pattern, "/")
+		} else {
+			pattern = "**/" + pattern
+		}
+		if !doublestar.ValidatePattern(pattern) {
+			return nil, fmt.Errorf("第 %d 行的匹配模式无效: %s", lineNo, pattern)
+		}
+
+		header, err := ParseHeader(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 行: %w", lineNo, err)
+		}
+		rules.rules = append(rules.rules, headerRule{pattern: pattern, header: header})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取 HTTP 头规则失败: %w", err)
+	}
+	return rules, nil
+}
+
+// Match 按规则顺序返回匹配相对路径 (以 / 分隔) 的 HTTP 头
+func (r *HeaderRules) Match(rel string) []Header {
+	if r == nil {
+		return nil
+	}
+	var headers []Header
+	for _, rule := range r.rules {
+		if doublestar.MatchUnvalidated(rule.pattern, rel) {
+			headers = append(headers, rule.header)
+		}
+	}
+	return headers
+}
+
+// forFile 按文件相对于上传目录 dirPath 的路径匹配 HeaderRules，返回该文件的上传选项。
+// 匹配规则得到的 HTTP 头在 Headers 之前，命令行指定的头优先
+func (o UploadOptions) forFile(dirPath, filePath string) UploadOptions {
+	if o.HeaderRules == nil {
+		return o
+	}
+
+	rel, err := filepath.Rel(dirPath, filePath)
+	if err != nil {
+		rel = filepath.Base(filePath)
+	}
+	o.Headers = append(o.HeaderRules.Match(filepath.ToSlash(rel)), o.Headers...)
+	o.HeaderRules = nil
+	return o
+}
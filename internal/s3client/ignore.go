@@ -0,0 +1,129 @@
+package s3client
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// IgnoreFileName 上传目录时读取的忽略规则文件，语法与 .gitignore 相同
+const IgnoreFileName = ".s3ctlignore"
+
+// ignoreRule 忽略规则文件中的一条规则
+type ignoreRule struct {
+	pattern string // doublestar 模式，相对于规则文件所在目录
+	negate  bool   // 以 ! 开头，重新包含之前被忽略的路径
+	dirOnly bool   // 以 / 结尾，只匹配目录
+}
+
+// ignoreMatcher 按目录记录各个 .s3ctlignore 中的规则。与 git 相同，子目录中的规则
+// 优先于上级目录，同一文件中靠后的规则优先；目录被忽略后其中的文件无法重新包含
+type ignoreMatcher struct {
+	rules map[string][]ignoreRule // 以相对目录 (根目录为空字符串) 为键
+}
+
+func newIgnoreMatcher() *ignoreMatcher {
+	return &ignoreMatcher{rules: map[string][]ignoreRule{}}
+}
+
+// load 读取目录中的规则文件，文件不存在时忽略
+func (m *ignoreMatcher) load(dirPath, relDir string) error {
+	file, err := os.Open(filepath.Join(dirPath, IgnoreFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("读取忽略规则失败: %w", err)
+	}
+	defer file.Close()
+
+	var rules []ignoreRule
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if rule, ok := parseIgnoreRule(scanner.Text()); ok {
+			rules = append(rules, rule)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("读取忽略规则失败: %w", err)
+	}
+	if len(rules) > 0 {
+		m.rules[relDir] = rules
+	}
+	return nil
+}
+
+// parseIgnoreRule 解析一行 gitignore 语法的规则，空行和注释返回 false
+func parseIgnoreRule(line string) (ignoreRule, bool) {
+	var rule ignoreRule
+
+	// 去掉行尾未转义的空格
+	line = strings.TrimRight(line, "\r")
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, "\\ ") {
+		line = line[:len(line)-1]
+	}
+	if line == "" || strings.HasPrefix(line, "#") {
+		return rule, false
+	}
+
+	switch {
+	case strings.HasPrefix(line, "!"):
+		rule.negate = true
+		line = line[1:]
+	case strings.HasPrefix(line, "\\!"), strings.HasPrefix(line, "\\#"):
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return rule, false
+	}
+
+	// 包含 / 的模式相对于规则文件所在目录，否则匹配任意层级的文件名
+	if strings.Contains(line, "/") {
+		line = strings.TrimPrefix(line, "/")
+	} else {
+		line = "**/" + line
+	}
+
+	// foo/** 只匹配 foo 之内的内容，不匹配 foo 本身
+	if strings.HasSuffix(line, "/**") {
+		line += "/*"
+	}
+
+	rule.pattern = line
+	return rule, true
+}
+
+// ignored 判断相对路径 (以 / 分隔) 是否被忽略
+func (m *ignoreMatcher) ignored(rel string, isDir bool) bool {
+	ignored := false
+
+	// 从根目录开始依次应用每一级上级目录中的规则
+	base := ""
+	for {
+		sub := strings.TrimPrefix(rel, base)
+		for _, rule := range m.rules[strings.TrimSuffix(base, "/")] {
+			if rule.dirOnly && !isDir {
+				continue
+			}
+			if doublestar.MatchUnvalidated(rule.pattern, sub) {
+				ignored = !rule.negate
+			}
+		}
+
+		i := strings.Index(sub, "/")
+		if i < 0 {
+			return ignored
+		}
+		base += sub[:i+1]
+	}
+}
@@ -0,0 +1,202 @@
+package s3client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// 镜像报告中的操作类型
+const (
+	MirrorActionCopy   = "copy"
+	MirrorActionSkip   = "skip"
+	MirrorActionDelete = "delete"
+	MirrorActionError  = "error"
+)
+
+// MirrorOptions 镜像选项
+type MirrorOptions struct {
+	Delete bool      // 删除目标端源端不存在的对象
+	Report io.Writer // 逐行写入 JSON 格式的报告，为 nil 时不写报告
+}
+
+// MirrorResult 镜像操作的统计结果
+type MirrorResult struct {
+	Copied  int
+	Skipped int
+	Deleted int
+	Failed  int
+}
+
+// MirrorRecord 镜像报告中每个对象的一行记录
+type MirrorRecord struct {
+	Key        string `json:"key"`
+	Action     string `json:"action"`
+	SourceETag string `json:"source_etag,omitempty"`
+	DestETag   string `json:"dest_etag,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Mirror 将源前缀镜像到目标前缀，复制目标端缺失或不同的对象，保留元数据和标签，
+// 复制后校验目标对象的大小和 ETag。dst 与当前客户端相同时在服务端复制，否则流式传输。
+// 单个对象失败时记录到报告并继续处理其余对象
+func (c *Client) Mirror(dst *Client, srcBucket, srcPrefix, dstBucket, dstPrefix string, mirrorOpts MirrorOptions) (MirrorResult, error) {
+	var (
+		result MirrorResult
+		failed []error
+	)
+
+	srcPrefix, dstPrefix = dirPrefix(srcPrefix), dirPrefix(dstPrefix)
+	same := dst == c
+	if same && srcBucket == dstBucket && (strings.HasPrefix(srcPrefix, dstPrefix) || strings.HasPrefix(dstPrefix, srcPrefix)) {
+		return result, fmt.Errorf("源前缀 %s 与目标前缀 %s 不能相互包含", srcPrefix, dstPrefix)
+	}
+
+	sources, err := c.listRemote(srcBucket, srcPrefix)
+	if err != nil {
+		return result, err
+	}
+	targets, err := dst.listRemote(dstBucket, dstPrefix)
+	if err != nil {
+		return result, err
+	}
+
+	var report *json.Encoder
+	if mirrorOpts.Report != nil {
+		report = json.NewEncoder(mirrorOpts.Report)
+	}
+	record := func(r MirrorRecord) {
+		if report == nil {
+			return
+		}
+		if err := report.Encode(r); err != nil && len(failed) == 0 {
+			failed = append(failed, fmt.Errorf("写入报告失败: %w", err))
+		}
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(sources)) {
+		if c.ctx.Err() != nil {
+			break
+		}
+
+		src := sources[key]
+		dstKey := joinObjectPath(dstPrefix, strings.TrimPrefix(key, srcPrefix))
+		target, exists := targets[dstKey]
+		delete(targets, dstKey)
+
+		if exists && !needsMirror(src, target) {
+			result.Skipped++
+			record(MirrorRecord{Key: key, Action: MirrorActionSkip, SourceETag: trimETag(src.ETag), DestETag: trimETag(target.ETag)})
+			continue
+		}
+
+		if c.dryRun {
+			planWrite(dryRunCopy, exists, srcBucket+"/"+key, dstBucket+"/"+dstKey, src.Size)
+			result.Copied++
+			record(MirrorRecord{Key: key, Action: MirrorActionCopy, SourceETag: trimETag(src.ETag)})
+			continue
+		}
+
+		fmt.Printf("镜像 %s/%s 到 %s/%s...\n", srcBucket, key, dstBucket, dstKey)
+		copied, err := c.mirrorObject(dst, srcBucket, key, dstBucket, dstKey)
+		if err != nil {
+			result.Failed++
+			failed = append(failed, fmt.Errorf("镜像对象 %s 失败: %w", key, err))
+			record(MirrorRecord{Key: key, Action: MirrorActionError, SourceETag: trimETag(src.ETag), Error: err.Error()})
+			continue
+		}
+		result.Copied++
+		record(MirrorRecord{Key: key, Action: MirrorActionCopy, SourceETag: trimETag(src.ETag), DestETag: trimETag(copied.ETag)})
+	}
+
+	// 剩余的目标对象在源端已不存在
+	if mirrorOpts.Delete && c.ctx.Err() == nil {
+		for _, key := range slices.Sorted(maps.Keys(targets)) {
+			target := targets[key]
+			if c.dryRun {
+				planAction(dryRunDelete, dstBucket+"/"+key, target.Size)
+				result.Deleted++
+				record(MirrorRecord{Key: key, Action: MirrorActionDelete, DestETag: trimETag(target.ETag)})
+				continue
+			}
+			fmt.Printf("删除 %s/%s\n", dstBucket, key)
+			if err := dst.removeObject(dstBucket, key); err != nil {
+				result.Failed++
+				failed = append(failed, fmt.Errorf("删除对象 %s 失败: %w", key, err))
+				record(MirrorRecord{Key: key, Action: MirrorActionError, DestETag: trimETag(target.ETag), Error: err.Error()})
+				continue
+			}
+			result.Deleted++
+			record(MirrorRecord{Key: key, Action: MirrorActionDelete, DestETag: trimETag(target.ETag)})
+		}
+	}
+
+	if err := c.ctx.Err(); err != nil {
+		failed = append(failed, fmt.Errorf("镜像已取消: %w", err))
+	}
+	return result, joinFailures("镜像", failed)
+}
+
+// mirrorObject 复制单个对象及其标签，并校验目标对象，返回目标对象的信息
+func (c *Client) mirrorObject(dst *Client, srcBucket, srcObject, dstBucket, dstObject string) (minio.ObjectInfo, error) {
+	var (
+		src minio.ObjectInfo
+		err error
+	)
+	if dst == c {
+		src, err = c.copyObject(srcBucket, srcObject, dstBucket, dstObject, CopyOptions{})
+	} else {
+		src, err = c.transferObject(dst, srcBucket, srcObject, dstBucket, dstObject, CopyOptions{})
+	}
+	if err != nil {
+		return minio.ObjectInfo{}, err
+	}
+
+	// 流式传输和分片复制不会带上标签，统一在复制后写入
+	if src.UserTagCount > 0 {
+		if err := c.copyTags(dst, srcBucket, srcObject, dstBucket, dstObject); err != nil {
+			return minio.ObjectInfo{}, err
+		}
+	}
+
+	info, err := dst.client.StatObject(dst.ctx, dstBucket, dstObject, minio.StatObjectOptions{})
+	if err != nil {
+		return info, fmt.Errorf("获取目标对象信息失败: %w", err)
+	}
+	return info, verifyCopy(src, info)
+}
+
+// copyTags 将源对象的标签写入目标对象
+func (c *Client) copyTags(dst *Client, srcBucket, srcObject, dstBucket, dstObject string) error {
+	objectTags, err := c.client.GetObjectTagging(c.ctx, srcBucket, srcObject, minio.GetObjectTaggingOptions{})
+	if err != nil {
+		return fmt.Errorf("获取源对象标签失败: %w", err)
+	}
+	if err := dst.client.PutObjectTagging(dst.ctx, dstBucket, dstObject, objectTags, minio.PutObjectTaggingOptions{}); err != nil {
+		return fmt.Errorf("写入目标对象标签失败: %w", err)
+	}
+	return nil
+}
+
+// needsMirror 判断目标对象是否需要重新复制。大小不同或 ETag 均为内容 MD5 且不相等时
+// 视为已变化；任一 ETag 来自分片上传时无法比较内容，按目标对象是否早于源对象判断
+func needsMirror(src, dst minio.ObjectInfo) bool {
+	if src.Size != dst.Size {
+		return true
+	}
+
+	srcETag, dstETag := trimETag(src.ETag), trimETag(dst.ETag)
+	if srcETag == dstETag {
+		return false
+	}
+	if !strings.Contains(srcETag, "-") && !strings.Contains(dstETag, "-") {
+		return true
+	}
+	return dst.LastModified.Truncate(time.Second).Before(src.LastModified.Truncate(time.Second))
+}
@@ -0,0 +1,117 @@
+package s3client
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// MoveResult 移动操作的统计结果
+type MoveResult struct {
+	Moved   int
+	Failed  int
+	Skipped int
+}
+
+// MoveObject 在服务端复制对象，校验目标对象与源对象一致后才删除源对象。
+// 复制或校验失败时源对象保持不变
+func (c *Client) MoveObject(srcBucket, srcObject, dstBucket, dstObject string, copyOpts CopyOptions) error {
+	if srcBucket == dstBucket && srcObject == dstObject {
+		return fmt.Errorf("源对象与目标对象相同: %s/%s", srcBucket, srcObject)
+	}
+	if c.dryRun {
+		src, err := c.planCopy(c, srcBucket, srcObject, dstBucket, dstObject)
+		if err != nil {
+			return err
+		}
+		planAction(dryRunDelete, srcBucket+"/"+srcObject, src.Size)
+		return nil
+	}
+	fmt.Printf("移动 %s/%s 到 %s/%s...\n", srcBucket, srcObject, dstBucket, dstObject)
+
+	src, err := c.copyObject(srcBucket, srcObject, dstBucket, dstObject, copyOpts)
+	if err != nil {
+		return err
+	}
+
+	dst, err := c.client.StatObject(c.ctx, dstBucket, dstObject, minio.StatObjectOptions{})
+	if err != nil {
+		return fmt.Errorf("获取目标对象信息失败: %w", err)
+	}
+	if err := verifyCopy(src, dst); err != nil {
+		return err
+	}
+
+	if err := c.removeObject(srcBucket, srcObject); err != nil {
+		return fmt.Errorf("删除源对象失败: %w", err)
+	}
+	return nil
+}
+
+// MoveDirectory 移动前缀下的所有对象。单个对象失败时保留其源对象并继续处理其余对象
+func (c *Client) MoveDirectory(srcBucket, srcPrefix, dstBucket, dstPrefix string, copyOpts CopyOptions) (MoveResult, error) {
+	var (
+		result MoveResult
+		failed []error
+	)
+
+	// 目标前缀位于源前缀之内时，移动后的对象会被再次列出
+	if srcBucket == dstBucket && strings.HasPrefix(joinObjectPath(dstPrefix, "x"), srcPrefix) {
+		return result, fmt.Errorf("目标前缀 %s 不能位于源前缀 %s 之内", dstPrefix, srcPrefix)
+	}
+
+	objects := c.ListObjects(srcBucket, srcPrefix, true, false)
+	for object := range objects {
+		if object.Err != nil {
+			failed = append(failed, fmt.Errorf("列出对象失败: %w", object.Err))
+			break
+		}
+
+		// 目录标记与其他对象一样移动，源前缀本身的目录标记移动为目标前缀的目录标记。
+		// 跳过移动后路径不变的对象，以及移动到存储桶根目录的目录标记
+		rel := strings.TrimPrefix(object.Key, srcPrefix)
+		dstObject := joinObjectPath(dstPrefix, rel)
+		if rel == "" {
+			dstObject = dirPrefix(dstPrefix)
+		}
+		if dstObject == "" || (srcBucket == dstBucket && dstObject == object.Key) {
+			result.Skipped++
+			continue
+		}
+
+		if c.ctx.Err() != nil {
+			result.Skipped++
+			continue
+		}
+
+		if err := c.MoveObject(srcBucket, object.Key, dstBucket, dstObject, copyOpts); err != nil {
+			result.Failed++
+			failed = append(failed, fmt.Errorf("移动对象 %s 失败: %w", object.Key, err))
+			continue
+		}
+		result.Moved++
+	}
+
+	if err := c.ctx.Err(); err != nil {
+		failed = append(failed, fmt.Errorf("移动已取消: %w", err))
+	}
+	return result, joinFailures("移动", failed)
+}
+
+// verifyCopy 校验目标对象与源对象一致。分片上传或分片复制生成的 ETag
+// 不是内容的 MD5，无法直接比较，此时只校验大小
+func verifyCopy(src, dst minio.ObjectInfo) error {
+	if src.Size != dst.Size {
+		return fmt.Errorf("目标对象大小 %d 与源对象大小 %d 不一致", dst.Size, src.Size)
+	}
+
+	srcETag, dstETag := trimETag(src.ETag), trimETag(dst.ETag)
+	if strings.Contains(srcETag, "-") || strings.Contains(dstETag, "-") {
+		return nil
+	}
+	if srcETag != dstETag {
+		return fmt.Errorf("目标对象 ETag %s 与源对象 ETag %s 不一致", dstETag, srcETag)
+	}
+	return nil
+}
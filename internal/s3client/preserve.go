@@ -0,0 +1,177 @@
+package s3client
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// 保存文件属性的元数据。单独的 mtime、mode、uid、gid 与 rclone 的格式相同，
+// s3cmd-attrs 与 s3cmd 的格式相同，两种格式同时写入，读取时优先使用前者
+const (
+	mtimeHeader      = "X-Amz-Meta-Mtime"       // 秒数，带 9 位小数的纳秒部分
+	modeHeader       = "X-Amz-Meta-Mode"        // 八进制的 st_mode，例如 0100644
+	uidHeader        = "X-Amz-Meta-Uid"         // 十进制
+	gidHeader        = "X-Amz-Meta-Gid"         // 十进制
+	s3cmdAttrsHeader = "X-Amz-Meta-S3cmd-Attrs" // 例如 gid:0/mode:33188/mtime:1700000000/uid:0，mode 为十进制
+)
+
+// sIFREG st_mode 中表示普通文件的类型位
+const sIFREG = 0o100000
+
+// fileAttrs 上传时保存、下载时恢复的 POSIX 文件属性
+type fileAttrs struct {
+	mtime    time.Time
+	mode     os.FileMode
+	uid, gid int
+	hasMtime bool
+	hasMode  bool
+	hasOwner bool
+}
+
+// statAttrs 读取本地文件的属性，不支持属主的平台上不记录 uid 和 gid
+func statAttrs(info os.FileInfo) fileAttrs {
+	attrs := fileAttrs{
+		mtime:    info.ModTime(),
+		mode:     info.Mode() & (os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky),
+		hasMtime: true,
+		hasMode:  true,
+	}
+	attrs.uid, attrs.gid, attrs.hasOwner = fileOwner(info)
+	return attrs
+}
+
+// metadata 返回保存属性的用户元数据
+func (a fileAttrs) metadata() map[string]string {
+	mode := unixMode(a.mode)
+	meta := map[string]string{
+		mtimeHeader: fmt.Sprintf("%d.%09d", a.mtime.Unix(), a.mtime.Nanosecond()),
+		modeHeader:  fmt.Sprintf("0%o", mode),
+	}
+	s3cmdAttrs := fmt.Sprintf("mode:%d/mtime:%d", mode, a.mtime.Unix())
+	if a.hasOwner {
+		meta[uidHeader] = strconv.Itoa(a.uid)
+		meta[gidHeader] = strconv.Itoa(a.gid)
+		s3cmdAttrs = fmt.Sprintf("gid:%d/%s/uid:%d", a.gid, s3cmdAttrs, a.uid)
+	}
+	meta[s3cmdAttrsHeader] = s3cmdAttrs
+	return meta
+}
+
+// objectAttrs 从对象的元数据中解析文件属性，无法解析的字段视为不存在
+func objectAttrs(objInfo minio.ObjectInfo) fileAttrs {
+	var attrs fileAttrs
+	s3cmd := parseS3cmdAttrs(objInfo.Metadata.Get(s3cmdAttrsHeader))
+
+	if mtime, ok := parseMtime(objInfo.Metadata.Get(mtimeHeader)); ok {
+		attrs.mtime, attrs.hasMtime = mtime, true
+	} else if sec, err := strconv.ParseInt(s3cmd["mtime"], 10, 64); err == nil {
+		attrs.mtime, attrs.hasMtime = time.Unix(sec, 0), true
+	}
+
+	if mode, err := strconv.ParseUint(objInfo.Metadata.Get(modeHeader), 8, 32); err == nil {
+		attrs.mode, attrs.hasMode = fileMode(uint32(mode)), true
+	} else if mode, err := strconv.ParseUint(s3cmd["mode"], 10, 32); err == nil {
+		attrs.mode, attrs.hasMode = fileMode(uint32(mode)), true
+	}
+
+	uid, gid := objInfo.Metadata.Get(uidHeader), objInfo.Metadata.Get(gidHeader)
+	if uid == "" && gid == "" {
+		uid, gid = s3cmd["uid"], s3cmd["gid"]
+	}
+	var uidErr, gidErr error
+	attrs.uid, uidErr = strconv.Atoi(uid)
+	attrs.gid, gidErr = strconv.Atoi(gid)
+	attrs.hasOwner = uidErr == nil && gidErr == nil
+	return attrs
+}
+
+// restore 将属性应用到本地文件
+func (a fileAttrs) restore(filePath string) error {
+	if a.hasOwner {
+		if err := chown(filePath, a.uid, a.gid); err != nil {
+			return fmt.Errorf("设置属主失败: %w", err)
+		}
+	}
+	if a.hasMode {
+		if err := os.Chmod(filePath, a.mode); err != nil {
+			return fmt.Errorf("设置权限失败: %w", err)
+		}
+	}
+	if a.hasMtime {
+		if err := os.Chtimes(filePath, a.mtime, a.mtime); err != nil {
+			return fmt.Errorf("设置修改时间失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// parseMtime 解析秒数形式的修改时间，可以带小数部分
+func parseMtime(s string) (time.Time, bool) {
+	secStr, fracStr, _ := strings.Cut(s, ".")
+	sec, err := strconv.ParseInt(secStr, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	var nsec int64
+	if fracStr != "" {
+		if len(fracStr) > 9 {
+			fracStr = fracStr[:9]
+		}
+		frac, err := strconv.ParseInt(fracStr, 10, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+		for i := len(fracStr); i < 9; i++ {
+			frac *= 10
+		}
+		nsec = frac
+	}
+	return time.Unix(sec, nsec), true
+}
+
+// parseS3cmdAttrs 解析 s3cmd-attrs 中以 / 分隔的 key:value
+func parseS3cmdAttrs(s string) map[string]string {
+	attrs := make(map[string]string)
+	for _, field := range strings.Split(s, "/") {
+		if key, value, ok := strings.Cut(field, ":"); ok {
+			attrs[key] = value
+		}
+	}
+	return attrs
+}
+
+// unixMode 将 os.FileMode 转换为普通文件的 st_mode
+func unixMode(mode os.FileMode) uint32 {
+	m := uint32(mode.Perm()) | sIFREG
+	if mode&os.ModeSetuid != 0 {
+		m |= 0o4000
+	}
+	if mode&os.ModeSetgid != 0 {
+		m |= 0o2000
+	}
+	if mode&os.ModeSticky != 0 {
+		m |= 0o1000
+	}
+	return m
+}
+
+// fileMode 将 st_mode 中的权限位和特殊位转换为 os.FileMode
+func fileMode(m uint32) os.FileMode {
+	mode := os.FileMode(m & 0o777)
+	if m&0o4000 != 0 {
+		mode |= os.ModeSetuid
+	}
+	if m&0o2000 != 0 {
+		mode |= os.ModeSetgid
+	}
+	if m&0o1000 != 0 {
+		mode |= os.ModeSticky
+	}
+	return mode
+}
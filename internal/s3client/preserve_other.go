@@ -0,0 +1,15 @@
+//go:build !unix
+
+package s3client
+
+import "os"
+
+// fileOwner 当前平台没有 POSIX 属主，不记录 uid 和 gid
+func fileOwner(os.FileInfo) (uid, gid int, ok bool) {
+	return 0, 0, false
+}
+
+// chown 当前平台没有 POSIX 属主，忽略
+func chown(string, int, int) error {
+	return nil
+}
@@ -0,0 +1,26 @@
+//go:build unix
+
+package s3client
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// fileOwner 返回文件的属主和属组
+func fileOwner(info os.FileInfo) (uid, gid int, ok bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, false
+	}
+	return int(stat.Uid), int(stat.Gid), true
+}
+
+// chown 设置文件的属主和属组，非 root 用户无权修改时忽略
+func chown(filePath string, uid, gid int) error {
+	if err := os.Lchown(filePath, uid, gid); err != nil && !errors.Is(err, os.ErrPermission) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,296 @@
+package s3client
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRefreshInterval 汇总进度的刷新间隔
+const DefaultRefreshInterval = 200 * time.Millisecond
+
+// progressSink 接收传输进度。minio 上传时通过 Read 回报已读取的字节，
+// 下载时通过 io.TeeReader 调用 Write 回报已写入的字节
+type progressSink interface {
+	io.Reader
+	io.Writer
+	preset(n int64)
+	restart(msg string) // 重试前清零进度，并在不破坏进度显示的位置输出 msg
+	done(err error)     // 文件传输结束，err 为最终结果
+}
+
+// progressFunc 根据传输总字节数创建进度跟踪器
+type progressFunc func(totalSize int64) progressSink
+
+// barProgress 为单个文件创建终端进度条
+func barProgress(totalSize int64) progressSink {
+	return newProgressReader(totalSize)
+}
+
+// multiProgress 并发传输时的汇总进度显示，进度条模式下包含一行总计以及每个工作协程一行，
+// 逐行输出模式下只在每个文件结束时输出一行结果，JSON 模式下输出每个文件的事件
+type multiProgress struct {
+	mu         sync.Mutex
+	operation  string
+	slots      []*workerSlot
+	totalFiles int
+	doneFiles  int
+	failed     int
+	skipped    skipCount // 遍历时跳过的文件，在最终汇总中显示
+	totalBytes int64
+	doneBytes  int64
+	startTime  time.Time
+	mode       ProgressMode // 不为 auto 的进度显示方式
+	lines      int          // 上次绘制的行数
+	quit       chan struct{}
+	done       chan struct{}
+}
+
+// workerSlot 单个工作协程的进度
+type workerSlot struct {
+	mp     *multiProgress
+	name   string
+	total  int64
+	bytes  int64
+	active bool
+	events eventTracker // JSON 模式下的事件状态
+}
+
+func newMultiProgress(operation string, workers int, mode ProgressMode) *multiProgress {
+	mp := &multiProgress{
+		operation: operation,
+		slots:     make([]*workerSlot, workers),
+		startTime: time.Now(),
+		mode:      mode,
+		quit:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	for i := range mp.slots {
+		mp.slots[i] = &workerSlot{mp: mp}
+	}
+
+	if mp.mode == ProgressBar {
+		go mp.loop()
+	} else {
+		close(mp.done)
+	}
+	return mp
+}
+
+// slot 返回第 i 个工作协程的进度
+func (mp *multiProgress) slot(i int) *workerSlot {
+	return mp.slots[i]
+}
+
+// addFile 记录新发现的待传输文件
+func (mp *multiProgress) addFile(size int64) {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+
+	mp.totalFiles++
+	mp.totalBytes += size
+}
+
+// stop 停止刷新并输出最终状态
+func (mp *multiProgress) stop() {
+	select {
+	case <-mp.quit:
+		return
+	default:
+		close(mp.quit)
+	}
+	<-mp.done
+
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	if mp.mode == ProgressBar {
+		mp.render(true)
+	}
+	fmt.Printf("%s完成 %d/%d 个文件 (%s/%s)，失败 %d 个%s\n",
+		mp.operation, mp.doneFiles, mp.totalFiles, formatBytes(mp.doneBytes), formatBytes(mp.totalBytes), mp.failed, mp.skipped)
+}
+
+func (mp *multiProgress) loop() {
+	defer close(mp.done)
+
+	ticker := time.NewTicker(DefaultRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			mp.mu.Lock()
+			mp.render(false)
+			mp.mu.Unlock()
+		case <-mp.quit:
+			return
+		}
+	}
+}
+
+// render 重绘进度区域，调用方需持有锁
+func (mp *multiProgress) render(final bool) {
+	var b strings.Builder
+
+	// 回到上次绘制区域的起始位置
+	if mp.lines > 0 {
+		fmt.Fprintf(&b, "\033[%dA", mp.lines)
+	}
+
+	elapsed := time.Since(mp.startTime).Seconds()
+	speed := int64(0)
+	if elapsed > 0 {
+		speed = int64(float64(mp.doneBytes) / elapsed)
+	}
+	fmt.Fprintf(&b, "\r\033[2K总计: %d/%d 个文件 (%s/%s) %s/s",
+		mp.doneFiles, mp.totalFiles, formatBytes(mp.doneBytes), formatBytes(mp.totalBytes), formatBytes(speed))
+	if mp.failed > 0 {
+		fmt.Fprintf(&b, " 失败 %d 个", mp.failed)
+	}
+	b.WriteString("\n")
+	lines := 1
+
+	// 结束时清除各工作协程的行
+	width := 50
+	if w, err := getTerminalWidth(); err == nil {
+		width = w
+	}
+	for i, slot := range mp.slots {
+		b.WriteString("\r\033[2K")
+		if !final && slot.active {
+			percent := int64(100)
+			if slot.total > 0 {
+				percent = slot.bytes * 100 / slot.total
+			}
+			line := fmt.Sprintf("  #%-2d %3d%% %-23s ", i+1, percent,
+				fmt.Sprintf("(%s/%s)", formatBytes(slot.bytes), formatBytes(slot.total)))
+			b.WriteString(line)
+			b.WriteString(truncateLeft(slot.name, width-len(line)-1))
+		}
+		b.WriteString("\n")
+		lines++
+	}
+
+	if final {
+		// 光标移回总计行之后，后续输出覆盖已清空的工作协程行
+		fmt.Fprintf(&b, "\033[%dA", len(mp.slots))
+		lines = 1
+	}
+
+	mp.lines = lines
+	fmt.Print(b.String())
+}
+
+// start 标记工作协程开始处理新文件
+func (s *workerSlot) start(name string) {
+	s.mp.mu.Lock()
+	defer s.mp.mu.Unlock()
+
+	s.name = name
+	s.total = 0
+	s.bytes = 0
+	s.active = true
+	s.events = eventTracker{key: name, startTime: time.Now()}
+}
+
+// tracker 作为 progressFunc 使用，记录当前文件的总大小并返回自身
+func (s *workerSlot) tracker(totalSize int64) progressSink {
+	s.mp.mu.Lock()
+	defer s.mp.mu.Unlock()
+
+	s.total = totalSize
+	if s.mp.mode == ProgressJSON {
+		s.events.begin(totalSize)
+	}
+	return s
+}
+
+// finish 标记当前文件处理结束
+func (s *workerSlot) finish(err error) {
+	s.mp.mu.Lock()
+	defer s.mp.mu.Unlock()
+
+	s.active = false
+	if err != nil {
+		s.mp.failed++
+	} else {
+		s.mp.doneFiles++
+	}
+
+	switch s.mp.mode {
+	case ProgressPlain:
+		status := "完成"
+		if err != nil {
+			status = "失败"
+		}
+		fmt.Printf("[%d/%d] %s%s %s\n", s.mp.doneFiles+s.mp.failed, s.mp.totalFiles, s.mp.operation, status, s.name)
+	case ProgressJSON:
+		s.events.end(s.bytes, err)
+	}
+}
+
+func (s *workerSlot) add(n int64) {
+	s.mp.mu.Lock()
+	defer s.mp.mu.Unlock()
+
+	// minio-go 内部重试请求时会重新读取数据，回报的字节不超过文件大小
+	if s.total > 0 {
+		n = min(n, s.total-s.bytes)
+	}
+	s.bytes += n
+	s.mp.doneBytes += n
+	if s.mp.mode == ProgressJSON {
+		s.events.tick(s.bytes)
+	}
+}
+
+func (s *workerSlot) Read(p []byte) (int, error) {
+	s.add(int64(len(p)))
+	return len(p), nil
+}
+
+func (s *workerSlot) Write(p []byte) (int, error) {
+	s.add(int64(len(p)))
+	return len(p), nil
+}
+
+func (s *workerSlot) preset(n int64) {
+	s.add(n)
+}
+
+// restart 从总计中减去当前文件已回报的字节，在进度区域之上输出 msg
+func (s *workerSlot) restart(msg string) {
+	s.mp.mu.Lock()
+	defer s.mp.mu.Unlock()
+
+	s.mp.doneBytes -= s.bytes
+	s.bytes = 0
+
+	if s.mp.mode == ProgressBar && s.mp.lines > 0 {
+		// 清除进度区域，下次刷新时在 msg 之后重新绘制
+		fmt.Printf("\033[%dA\r\033[J", s.mp.lines)
+		s.mp.lines = 0
+	}
+	fmt.Println(msg)
+	if s.mp.mode == ProgressJSON {
+		s.events.begin(s.total)
+	}
+}
+
+// done 由工作协程调用 finish 统计结果，这里不需要处理
+func (s *workerSlot) done(error) {}
+
+// truncateLeft 截断过长的路径，保留末尾部分
+func truncateLeft(name string, width int) string {
+	if width <= 3 {
+		return filepath.Base(name)
+	}
+	runes := []rune(name)
+	if len(runes) <= width {
+		return name
+	}
+	return "..." + string(runes[len(runes)-width+3:])
+}
@@ -0,0 +1,258 @@
+package s3client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// ProgressMode 进度显示方式
+type ProgressMode string
+
+const (
+	ProgressAuto  ProgressMode = "auto"  // 标准输出为终端时显示进度条，否则逐行输出
+	ProgressBar   ProgressMode = "bar"   // 终端进度条
+	ProgressPlain ProgressMode = "plain" // 每个文件结束时输出一行，不使用 \r 和终端控制符
+	ProgressJSON  ProgressMode = "json"  // 向标准错误逐行输出 JSON 事件
+	ProgressNone  ProgressMode = "none"  // 不显示进度
+)
+
+// ParseProgressMode 解析进度显示方式
+func ParseProgressMode(s string) (ProgressMode, error) {
+	switch mode := ProgressMode(s); mode {
+	case ProgressAuto, ProgressBar, ProgressPlain, ProgressJSON, ProgressNone:
+		return mode, nil
+	}
+	return "", fmt.Errorf("无效的进度显示方式 %q，可选值为 auto、bar、plain、json 和 none", s)
+}
+
+// SetProgressMode 设置进度显示方式
+func (c *Client) SetProgressMode(mode ProgressMode) {
+	c.progress = mode
+}
+
+// progressMode 返回实际使用的进度显示方式，auto 在标准输出为终端时使用进度条，否则逐行输出
+func (c *Client) progressMode() ProgressMode {
+	if c.progress != ProgressAuto && c.progress != "" {
+		return c.progress
+	}
+	if term.IsTerminal(int(os.Stdout.Fd())) {
+		return ProgressBar
+	}
+	return ProgressPlain
+}
+
+// fileProgress 按进度显示方式为单个文件创建 progressFunc，key 为事件和结果中显示的名称
+func (c *Client) fileProgress(key string) progressFunc {
+	switch c.progressMode() {
+	case ProgressPlain:
+		return func(totalSize int64) progressSink {
+			return &plainProgress{key: key, total: totalSize, startTime: time.Now()}
+		}
+	case ProgressJSON:
+		return func(totalSize int64) progressSink {
+			return newJSONProgress(key, totalSize)
+		}
+	case ProgressNone:
+		return func(int64) progressSink {
+			return quietProgress{}
+		}
+	}
+	return barProgress
+}
+
+// averageRate 返回 start 以来的平均速度 (每秒字节数)
+func averageRate(bytes int64, start time.Time) int64 {
+	elapsed := time.Since(start).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return int64(float64(bytes) / elapsed)
+}
+
+// clampProgress 累加已传输的字节数。minio-go 内部重试请求时会重新读取数据，已知总大小时不超过总大小
+func clampProgress(bytes, n, total int64) int64 {
+	if total >= 0 {
+		return min(bytes+n, total)
+	}
+	return bytes + n
+}
+
+// plainProgress 逐行输出的进度，文件传输成功后输出一行大小和平均速度
+type plainProgress struct {
+	mu        sync.Mutex
+	key       string
+	total     int64
+	bytes     int64
+	startTime time.Time
+}
+
+func (p *plainProgress) Read(b []byte) (int, error) {
+	p.preset(int64(len(b)))
+	return len(b), nil
+}
+
+func (p *plainProgress) Write(b []byte) (int, error) {
+	p.preset(int64(len(b)))
+	return len(b), nil
+}
+
+func (p *plainProgress) preset(n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.bytes = clampProgress(p.bytes, n, p.total)
+}
+
+func (p *plainProgress) restart(msg string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fmt.Println(msg)
+	p.bytes = 0
+	p.startTime = time.Now()
+}
+
+func (p *plainProgress) done(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err != nil {
+		return
+	}
+	fmt.Printf("完成 %s (%s，%s/s)\n", p.key, formatBytes(p.bytes), formatBytes(averageRate(p.bytes, p.startTime)))
+}
+
+// quietProgress 不显示进度，只输出重试信息
+type quietProgress struct{}
+
+func (quietProgress) Read(b []byte) (int, error)  { return len(b), nil }
+func (quietProgress) Write(b []byte) (int, error) { return len(b), nil }
+func (quietProgress) preset(int64)                {}
+func (quietProgress) restart(msg string)          { fmt.Println(msg) }
+func (quietProgress) done(error)                  {}
+
+// progressEvent JSON 模式下的进度事件，每个事件占一行
+type progressEvent struct {
+	Event string `json:"event"` // start、progress、done 或 error
+	Key   string `json:"key"`
+	Bytes int64  `json:"bytes"`
+	Total int64  `json:"total"` // 总大小未知时为 -1
+	Rate  int64  `json:"rate"`  // 平均速度，每秒字节数
+	Error string `json:"error,omitempty"`
+}
+
+var (
+	eventMu     sync.Mutex
+	eventOutput io.Writer = os.Stderr // 进度事件的输出位置
+)
+
+// emitEvent 输出一个进度事件，并发的工作协程共用同一个输出，逐行加锁写入
+func emitEvent(e progressEvent) {
+	eventMu.Lock()
+	defer eventMu.Unlock()
+
+	_ = json.NewEncoder(eventOutput).Encode(e)
+}
+
+// eventTracker 记录单个文件的事件状态，调用方负责加锁
+type eventTracker struct {
+	key       string
+	total     int64
+	startTime time.Time
+	lastEmit  time.Time
+}
+
+// begin 开始新的一次传输并输出 start 事件，重试时也会重新输出
+func (t *eventTracker) begin(total int64) {
+	now := time.Now()
+	t.total = total
+	t.startTime = now
+	t.lastEmit = now
+	t.emit("start", 0, nil)
+}
+
+// tick 距上次输出超过刷新间隔时输出 progress 事件
+func (t *eventTracker) tick(bytes int64) {
+	if time.Since(t.lastEmit) < DefaultRefreshInterval {
+		return
+	}
+	t.lastEmit = time.Now()
+	t.emit("progress", bytes, nil)
+}
+
+// end 输出 done 或 error 事件
+func (t *eventTracker) end(bytes int64, err error) {
+	if err != nil {
+		t.emit("error", bytes, err)
+		return
+	}
+	t.emit("done", bytes, nil)
+}
+
+func (t *eventTracker) emit(event string, bytes int64, err error) {
+	e := progressEvent{
+		Event: event,
+		Key:   t.key,
+		Bytes: bytes,
+		Total: t.total,
+		Rate:  averageRate(bytes, t.startTime),
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	emitEvent(e)
+}
+
+// jsonProgress JSON 模式下单个文件的进度
+type jsonProgress struct {
+	mu     sync.Mutex
+	events eventTracker
+	bytes  int64
+}
+
+func newJSONProgress(key string, totalSize int64) *jsonProgress {
+	p := &jsonProgress{events: eventTracker{key: key}}
+	p.events.begin(totalSize)
+	return p
+}
+
+func (p *jsonProgress) Read(b []byte) (int, error) {
+	p.preset(int64(len(b)))
+	return len(b), nil
+}
+
+func (p *jsonProgress) Write(b []byte) (int, error) {
+	p.preset(int64(len(b)))
+	return len(b), nil
+}
+
+func (p *jsonProgress) preset(n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.bytes = clampProgress(p.bytes, n, p.events.total)
+	p.events.tick(p.bytes)
+}
+
+// restart 在标准输出输出重试信息，清零进度并重新输出 start 事件
+func (p *jsonProgress) restart(msg string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fmt.Println(msg)
+	p.bytes = 0
+	p.events.begin(p.events.total)
+}
+
+func (p *jsonProgress) done(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.events.end(p.bytes, err)
+}
@@ -0,0 +1,104 @@
+package s3client
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// minRateBurst 令牌桶的最小容量，保证每次读写至少可以取走一个缓冲区的令牌
+const minRateBurst = 32 << 10
+
+// rateLimiter 令牌桶限速器。同一个客户端的所有传输共享一个限速器，
+// 限制的是所有并发工作协程的总吞吐量，而不是每个协程各自的速度
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // 每秒补充的令牌数，即每秒字节数
+	burst  float64 // 令牌桶容量
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter 创建每秒 bytesPerSecond 字节的限速器，不大于 0 时返回 nil 表示不限速。
+// 令牌桶容量为 100ms 的流量，避免空闲后出现过大的突发
+func newRateLimiter(bytesPerSecond int64) *rateLimiter {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+	rate := float64(bytesPerSecond)
+	burst := max(rate/10, minRateBurst)
+	return &rateLimiter{
+		rate:   rate,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// reserve 取走 n 个令牌并返回需要等待的时间。令牌不足时先透支，
+// 后续的调用者需要额外等待透支的部分，因此总速度不会超过限制
+func (l *rateLimiter) reserve(now time.Time, n int) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	l.tokens -= float64(n)
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// wait 取走 n 个令牌，令牌不足时等待，ctx 取消时提前返回
+func (l *rateLimiter) wait(ctx context.Context, n int) error {
+	d := l.reserve(time.Now(), n)
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// throttledProgress 在回报进度之前按限速器等待。上传时 minio 读取数据后同步调用 Read，
+// 下载时 io.TeeReader 写入数据后同步调用 Write，阻塞在这里即可限制传输速度，
+// 进度条在等待之后才计数，显示的就是限速后的速度
+type throttledProgress struct {
+	progressSink
+	limiter *rateLimiter
+	ctx     context.Context
+}
+
+func (t *throttledProgress) Read(p []byte) (int, error) {
+	if err := t.limiter.wait(t.ctx, len(p)); err != nil {
+		return 0, err
+	}
+	return t.progressSink.Read(p)
+}
+
+func (t *throttledProgress) Write(p []byte) (int, error) {
+	if err := t.limiter.wait(t.ctx, len(p)); err != nil {
+		return 0, err
+	}
+	return t.progressSink.Write(p)
+}
+
+// SetRateLimit 设置所有传输的总速度上限 (每秒字节数)，0 表示不限速，覆盖配置中的 limit_rate
+func (c *Client) SetRateLimit(bytesPerSecond int64) {
+	c.limiter = newRateLimiter(bytesPerSecond)
+}
+
+// throttled 为进度跟踪器加上限速，未设置限速时原样返回
+func (c *Client) throttled(progress progressSink) progressSink {
+	if c.limiter == nil {
+		return progress
+	}
+	return &throttledProgress{progressSink: progress, limiter: c.limiter, ctx: c.ctx}
+}
@@ -0,0 +1,320 @@
+package s3client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+const (
+	DefaultPartJobs           = 4        // 续传时默认的分片并发数
+	DefaultMultipartThreshold = 16 << 20 // 未指定分片大小时启用续传的文件大小阈值
+)
+
+// resumable 判断指定大小的文件是否需要走可续传的分片上传
+func (o UploadOptions) resumable(size int64) bool {
+	if !o.Resume {
+		return false
+	}
+	if o.PartSize > 0 {
+		return size > int64(o.PartSize)
+	}
+	return size > DefaultMultipartThreshold
+}
+
+// uploadState 断点续传的本地状态
+type uploadState struct {
+	Bucket   string         `json:"bucket"`
+	Object   string         `json:"object"`
+	FilePath string         `json:"file_path"`
+	Size     int64          `json:"size"`
+	ModTime  time.Time      `json:"mod_time"`
+	PartSize int64          `json:"part_size"`
+	UploadID string         `json:"upload_id"`
+	Parts    []uploadedPart `json:"parts"`
+}
+
+// uploadedPart 已完成上传的分片
+type uploadedPart struct {
+	PartNumber int    `json:"part_number"`
+	ETag       string `json:"etag"`
+	Size       int64  `json:"size"`
+}
+
+// uploadStateDir 返回续传状态文件所在目录
+func uploadStateDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("获取缓存目录失败: %w", err)
+	}
+	return filepath.Join(dir, "s3ctl", "uploads"), nil
+}
+
+// uploadStatePath 根据源文件和目标对象计算状态文件路径
+func uploadStatePath(bucketName, objectName, absPath string) (string, error) {
+	dir, err := uploadStateDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(bucketName + "\x00" + objectName + "\x00" + absPath))
+	return filepath.Join(dir, hex.EncodeToString(sum[:16])+".json"), nil
+}
+
+// loadUploadState 读取状态文件，文件不存在时返回 nil
+func loadUploadState(path string) (*uploadState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("读取续传状态失败: %w", err)
+	}
+
+	var state uploadState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("解析续传状态失败: %w", err)
+	}
+	return &state, nil
+}
+
+// save 原子地写入状态文件
+func (s *uploadState) save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("创建状态目录失败: %w", err)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化续传状态失败: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("写入续传状态失败: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// matchesFile 检查本地文件自上次上传以来是否被修改
+func (s *uploadState) matchesFile(info os.FileInfo) bool {
+	return s.Size == info.Size() && s.ModTime.Equal(info.ModTime())
+}
+
+// uploadFileResumable 以可续传的方式分片上传文件
+func (c *Client) uploadFileResumable(bucketName, filePath, objectName string, file *os.File, fileInfo os.FileInfo, opts minio.PutObjectOptions, uploadOpts UploadOptions, newProgress progressFunc) error {
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return fmt.Errorf("获取绝对路径失败: %w", err)
+	}
+
+	statePath, err := uploadStatePath(bucketName, objectName, absPath)
+	if err != nil {
+		return err
+	}
+
+	state, err := loadUploadState(statePath)
+	if err != nil {
+		return err
+	}
+
+	// 本地文件已变化时拒绝续传
+	if state != nil && !state.matchesFile(fileInfo) {
+		return fmt.Errorf("本地文件 %s 在上次中断后已被修改，拒绝续传；如需重新上传请删除状态文件 %s", filePath, statePath)
+	}
+
+	core := minio.Core{Client: c.client}
+
+	// 确认服务端仍保留该未完成的分片上传
+	if state != nil {
+		parts, err := c.findIncompleteUpload(core, state)
+		if err != nil {
+			return err
+		}
+		if parts == nil {
+			fmt.Printf("未找到 %s/%s 的未完成上传，重新开始上传\n", bucketName, objectName)
+			state = nil
+		} else {
+			state.Parts = parts
+			fmt.Printf("续传 %s/%s，已完成 %d 个分片\n", bucketName, objectName, len(parts))
+		}
+	}
+
+	// 没有可续传的上传时创建新的分片上传
+	if state == nil {
+		_, partSize, _, err := minio.OptimalPartInfo(fileInfo.Size(), uploadOpts.PartSize)
+		if err != nil {
+			return fmt.Errorf("计算分片大小失败: %w", err)
+		}
+
+		uploadID, err := core.NewMultipartUpload(c.ctx, bucketName, objectName, opts)
+		if err != nil {
+			return fmt.Errorf("创建分片上传失败: %w", err)
+		}
+
+		state = &uploadState{
+			Bucket:   bucketName,
+			Object:   objectName,
+			FilePath: absPath,
+			Size:     fileInfo.Size(),
+			ModTime:  fileInfo.ModTime(),
+			PartSize: partSize,
+			UploadID: uploadID,
+		}
+		if err := state.save(statePath); err != nil {
+			return err
+		}
+	}
+
+	if err := c.uploadMissingParts(core, file, state, statePath, uploadOpts.PartJobs, c.throttled(newProgress(state.Size))); err != nil {
+		return err
+	}
+
+	// 按分片编号排序后完成上传
+	completeParts := make([]minio.CompletePart, 0, len(state.Parts))
+	for _, part := range state.Parts {
+		completeParts = append(completeParts, minio.CompletePart{PartNumber: part.PartNumber, ETag: part.ETag})
+	}
+	sort.Slice(completeParts, func(i, j int) bool {
+		return completeParts[i].PartNumber < completeParts[j].PartNumber
+	})
+
+	if _, err := core.CompleteMultipartUpload(c.ctx, bucketName, objectName, state.UploadID, completeParts, opts); err != nil {
+		return fmt.Errorf("完成分片上传失败: %w", err)
+	}
+
+	if err := os.Remove(statePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("删除续传状态失败: %w", err)
+	}
+	return nil
+}
+
+// findIncompleteUpload 查找状态文件记录的未完成上传，返回服务端确认过的分片，
+// 上传已不存在时返回 nil
+func (c *Client) findIncompleteUpload(core minio.Core, state *uploadState) ([]uploadedPart, error) {
+	found := false
+	for upload := range c.client.ListIncompleteUploads(c.ctx, state.Bucket, state.Object, false) {
+		if upload.Err != nil {
+			return nil, fmt.Errorf("列出未完成上传失败: %w", upload.Err)
+		}
+		if upload.Key == state.Object && upload.UploadID == state.UploadID {
+			found = true
+		}
+	}
+	if !found {
+		return nil, nil
+	}
+
+	// 以服务端记录的分片为准，只保留 ETag 与本地状态一致的分片
+	local := make(map[int]string, len(state.Parts))
+	for _, part := range state.Parts {
+		local[part.PartNumber] = part.ETag
+	}
+
+	parts := []uploadedPart{}
+	marker := 0
+	for {
+		result, err := core.ListObjectParts(c.ctx, state.Bucket, state.Object, state.UploadID, marker, 1000)
+		if err != nil {
+			return nil, fmt.Errorf("列出已上传分片失败: %w", err)
+		}
+		for _, part := range result.ObjectParts {
+			if etag, ok := local[part.PartNumber]; ok && trimETag(etag) == trimETag(part.ETag) {
+				parts = append(parts, uploadedPart{PartNumber: part.PartNumber, ETag: part.ETag, Size: part.Size})
+			}
+		}
+		if !result.IsTruncated {
+			break
+		}
+		marker = result.NextPartNumberMarker
+	}
+	return parts, nil
+}
+
+// uploadMissingParts 并发上传尚未完成的分片，每完成一个分片即更新状态文件
+func (c *Client) uploadMissingParts(core minio.Core, file *os.File, state *uploadState, statePath string, jobs uint, progress progressSink) error {
+	if jobs == 0 {
+		jobs = DefaultPartJobs
+	}
+
+	done := make(map[int]bool, len(state.Parts))
+	var uploadedBytes int64
+	for _, part := range state.Parts {
+		done[part.PartNumber] = true
+		uploadedBytes += part.Size
+	}
+
+	totalParts := int((state.Size + state.PartSize - 1) / state.PartSize)
+	pending := make(chan int, totalParts)
+	for partNumber := 1; partNumber <= totalParts; partNumber++ {
+		if !done[partNumber] {
+			pending <- partNumber
+		}
+	}
+	close(pending)
+
+	// 已完成的分片直接计入进度
+	progress.preset(uploadedBytes)
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	for i := uint(0); i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for partNumber := range pending {
+				mu.Lock()
+				failed := firstErr != nil
+				mu.Unlock()
+				if failed || c.ctx.Err() != nil {
+					return
+				}
+
+				offset := int64(partNumber-1) * state.PartSize
+				size := min(state.PartSize, state.Size-offset)
+				reader := io.TeeReader(io.NewSectionReader(file, offset, size), progress)
+
+				part, err := core.PutObjectPart(c.ctx, state.Bucket, state.Object, state.UploadID, partNumber, reader, size, minio.PutObjectPartOptions{})
+
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = fmt.Errorf("上传分片 %d 失败: %w", partNumber, err)
+					}
+				} else {
+					state.Parts = append(state.Parts, uploadedPart{PartNumber: partNumber, ETag: part.ETag, Size: size})
+					if err := state.save(statePath); err != nil && firstErr == nil {
+						firstErr = err
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := c.ctx.Err(); err != nil {
+		return fmt.Errorf("上传已中断，可使用 --resume 继续: %w", err)
+	}
+	return firstErr
+}
+
+// trimETag 去掉 ETag 两侧的引号
+func trimETag(etag string) string {
+	if len(etag) >= 2 && etag[0] == '"' && etag[len(etag)-1] == '"' {
+		return etag[1 : len(etag)-1]
+	}
+	return etag
+}
@@ -0,0 +1,154 @@
+package s3client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// 默认的重试策略
+const (
+	DefaultRetries      = 3
+	DefaultRetryMaxWait = 30 * time.Second
+	retryBaseWait       = time.Second // 第一次重试前的基础等待时间
+)
+
+// RetryPolicy 单个对象传输失败后的重试策略
+type RetryPolicy struct {
+	Retries int           // 最多重试次数，0 表示不重试
+	MaxWait time.Duration // 两次尝试之间的最长等待时间
+}
+
+// DefaultRetryPolicy 返回默认的重试策略
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{Retries: DefaultRetries, MaxWait: DefaultRetryMaxWait}
+}
+
+// backoff 返回第 attempt 次重试前的等待时间。等待时间从 1 秒开始按指数增长，
+// 不超过 MaxWait，实际等待在其一半到全部之间随机选取，jitter 为 [0, 1) 的随机数，
+// 避免并发的工作协程同时重试
+func (p RetryPolicy) backoff(attempt int, jitter float64) time.Duration {
+	wait := p.MaxWait
+	if attempt < 32 {
+		wait = min(retryBaseWait<<(attempt-1), p.MaxWait)
+	}
+	return wait/2 + time.Duration(jitter*float64(wait/2))
+}
+
+// retryableCodes 可以重试的 S3 错误码
+var retryableCodes = map[string]bool{
+	"RequestTimeout":     true,
+	"InternalError":      true,
+	"ServiceUnavailable": true,
+	"SlowDown":           true,
+	"Throttling":         true,
+	"RequestThrottled":   true,
+	"OperationAborted":   true,
+}
+
+// isRetryable 判断错误是否为暂时性的错误：可重试的 S3 错误码、5xx 和 429 状态码，
+// 以及连接被重置、超时等网络错误。取消操作不会重试
+func isRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var resp minio.ErrorResponse
+	if errors.As(err, &resp) {
+		return retryableCodes[resp.Code] ||
+			resp.StatusCode == http.StatusTooManyRequests ||
+			resp.StatusCode >= http.StatusInternalServerError
+	}
+
+	// syscall.Errno 也实现了 net.Error，只按网络操作和 HTTP 请求的错误类型判断，
+	// 避免把打开本地文件失败之类的错误当作暂时性错误
+	var (
+		opErr  *net.OpError
+		urlErr *url.Error
+	)
+	// HTTP 请求的错误只在超时或连接中断时重试，证书校验失败之类的错误重试也不会成功
+	if errors.As(err, &urlErr) {
+		return urlErr.Timeout() ||
+			errors.As(urlErr.Err, &opErr) ||
+			errors.Is(urlErr.Err, io.EOF) ||
+			errors.Is(urlErr.Err, io.ErrUnexpectedEOF) ||
+			errors.Is(urlErr.Err, syscall.ECONNRESET)
+	}
+	return errors.As(err, &opErr) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE)
+}
+
+// SetRetryPolicy 设置单个对象传输失败后的重试策略，覆盖配置中的 retries 和 retry_max_wait
+func (c *Client) SetRetryPolicy(policy RetryPolicy) {
+	c.retry = policy
+}
+
+// RetryPolicy 返回当前的重试策略
+func (c *Client) RetryPolicy() RetryPolicy {
+	return c.retry
+}
+
+// retryTracker 在多次尝试之间复用同一个进度跟踪器
+type retryTracker struct {
+	newProgress progressFunc
+	sink        progressSink
+}
+
+func (t *retryTracker) progress(totalSize int64) progressSink {
+	if t.sink == nil {
+		t.sink = t.newProgress(totalSize)
+	}
+	return t.sink
+}
+
+// withRetry 执行幂等的操作 fn，遇到暂时性的错误时按重试策略等待后重新执行。
+// 重试前清零 key 的进度并输出重试信息，结束时将最终结果交给进度跟踪器。
+// newProgress 为 nil 时表示操作没有进度显示
+func (c *Client) withRetry(key string, newProgress progressFunc, fn func(newProgress progressFunc) error) (err error) {
+	tracker := &retryTracker{newProgress: newProgress}
+	defer func() {
+		// 创建进度跟踪器之前就失败时 (例如打开本地文件或获取对象信息失败) 以未知大小补建，
+		// 使 JSON 模式下每个失败的对象都有 error 事件
+		if err != nil && tracker.sink == nil && newProgress != nil {
+			tracker.progress(-1)
+		}
+		if tracker.sink != nil {
+			tracker.sink.done(err)
+		}
+	}()
+
+	for attempt := 1; ; attempt++ {
+		err = fn(tracker.progress)
+		if err == nil || attempt > c.retry.Retries || !isRetryable(err) || c.ctx.Err() != nil {
+			return err
+		}
+
+		wait := c.retry.backoff(attempt, rand.Float64())
+		msg := fmt.Sprintf("%s 失败，%s 后第 %d/%d 次重试: %v", key, wait.Round(100*time.Millisecond), attempt, c.retry.Retries, err)
+		if tracker.sink != nil {
+			tracker.sink.restart(msg)
+		} else {
+			fmt.Println(msg)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-c.ctx.Done():
+			timer.Stop()
+			return err
+		}
+	}
+}
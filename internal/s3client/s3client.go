@@ -18,20 +18,53 @@ func (c *Client) IsDirectory(objectPath string) bool {
 	return false
 }
 
+// DeleteOptions 删除目录的选项
+type DeleteOptions struct {
+	Filter    *Filter         // 只删除通过筛选的对象，nil 表示删除全部对象
+	KeepGoing bool            // 单个对象删除失败后继续删除其余对象，结束后返回 PartialError
+	Only      map[string]bool // 只删除其中列出的对象名称，nil 表示全部
+}
+
 // DeleteDirectory 递归删除目录下的所有对象
-func (c *Client) DeleteDirectory(bucketName, objectPath string) error {
-	objects := c.ListObjects(bucketName, objectPath, true, false)
+func (c *Client) DeleteDirectory(bucketName, objectPath string, deleteOpts DeleteOptions) error {
+	var (
+		deleted  int
+		failures []Failure
+	)
 
+	objects := c.ListObjects(bucketName, objectPath, true, false)
 	for object := range objects {
 		if object.Err != nil {
-			return object.Err
+			return partialResult("删除", deleted, failures, []error{object.Err})
+		}
+
+		if !deleteOpts.Filter.MatchObject(objectPath, object.Key) {
+			continue
+		}
+		if deleteOpts.Only != nil && !deleteOpts.Only[object.Key] {
+			continue
+		}
+
+		if c.dryRun {
+			planAction(dryRunDelete, bucketName+"/"+object.Key, object.Size)
+			continue
 		}
 
 		if err := c.DeleteObject(bucketName, object.Key); err != nil {
-			return err
+			if !deleteOpts.KeepGoing {
+				return err
+			}
+			failures = append(failures, Failure{Name: object.Key, Err: err})
+			continue
 		}
+		deleted++
 	}
-	return nil
+
+	var fatal []error
+	if err := c.ctx.Err(); err != nil {
+		fatal = append(fatal, fmt.Errorf("删除已取消: %w", err))
+	}
+	return partialResult("删除", deleted, failures, fatal)
 }
 
 // DeleteObject 删除指定对象
@@ -39,6 +72,9 @@ func (c *Client) DeleteObject(bucketName, objectPath string) error {
 	if objectPath == "" {
 		return fmt.Errorf("对象路径不能为空")
 	}
+	if c.dryRun {
+		return c.planDelete(bucketName, objectPath)
+	}
 	// 实现删除对象的逻辑
 	fmt.Printf("正在删除对象 %s/%s...\n", bucketName, objectPath)
 
@@ -52,10 +88,16 @@ func (c *Client) DeleteObject(bucketName, objectPath string) error {
 	}
 
 	// 使用 minio 客户端删除对象
-	err := c.client.RemoveObject(c.ctx, bucketName, objectPath, minio.RemoveObjectOptions{})
-	if err != nil {
+	if err := c.removeObject(bucketName, objectPath); err != nil {
 		return fmt.Errorf("删除对象失败: %w", err)
 	}
 
 	return nil
 }
+
+// removeObject 删除对象，删除是幂等的，暂时性的错误按重试策略重试
+func (c *Client) removeObject(bucketName, objectName string) error {
+	return c.withRetry(bucketName+"/"+objectName, nil, func(progressFunc) error {
+		return c.client.RemoveObject(c.ctx, bucketName, objectName, minio.RemoveObjectOptions{})
+	})
+}
@@ -0,0 +1,101 @@
+package s3client
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// DefaultStreamPartSize 未指定分片大小时流式上传使用的分片大小，决定了上传时占用的内存
+const DefaultStreamPartSize = 16 << 20
+
+// sniffLen 扩展名未知时按内容识别 Content-Type 所需的数据流开头字节数
+const sniffLen = 3072
+
+// UploadStream 将大小未知的数据流以分片方式上传，内存占用为分片大小乘以分片并发数
+func (c *Client) UploadStream(bucketName, objectName string, reader io.Reader, uploadOpts UploadOptions) error {
+	if objectName == "" || strings.HasSuffix(objectName, "/") {
+		return fmt.Errorf("从标准输入上传时必须指定完整的对象名称")
+	}
+	if c.dryRun {
+		exists, err := c.objectExists(bucketName, objectName)
+		if err != nil {
+			return err
+		}
+		planWrite(dryRunUpload, exists, "标准输入", bucketName+"/"+objectName, -1)
+		return nil
+	}
+	fmt.Printf("上传标准输入到 %s/%s...\n", bucketName, objectName)
+
+	// 预读数据流的开头，扩展名未知时用于识别 Content-Type
+	buffered := bufio.NewReaderSize(reader, sniffLen)
+	head, _ := buffered.Peek(sniffLen)
+	reader = buffered
+
+	opts := c.streamOptions(objectName, uploadOpts, head)
+	progress := c.fileProgress(objectName)(-1)
+	opts.Progress = c.throttled(progress)
+
+	client := c.client
+	if uploadOpts.Checksum.IsSet() {
+		opts.Checksum = uploadOpts.Checksum
+		client = c.trailer
+	}
+
+	_, err := client.PutObject(c.ctx, bucketName, objectName, reader, -1, opts)
+	progress.done(err)
+	if err != nil {
+		return fmt.Errorf("上传数据流失败: %w", err)
+	}
+	return nil
+}
+
+// streamOptions 返回流式上传的对象选项，head 为数据流开头的字节，扩展名未知时用于识别 Content-Type
+func (c *Client) streamOptions(objectName string, uploadOpts UploadOptions, head []byte) minio.PutObjectOptions {
+	// 设置对象选项
+	opts := minio.PutObjectOptions{
+		ContentType: c.contentType(objectName, uploadOpts, bytes.NewReader(head)),
+		PartSize:    uploadOpts.PartSize,
+	}
+	if opts.PartSize == 0 {
+		opts.PartSize = DefaultStreamPartSize
+	}
+
+	// 如果是公开文件，设置权限
+	if uploadOpts.IsPublic {
+		opts.UserMetadata = map[string]string{"x-amz-acl": "public-read"}
+	}
+
+	// 命令行和规则文件指定的 HTTP 头与用户元数据，规则按对象名称中的文件名匹配
+	applyHeaders(&opts, uploadOpts.forFile(".", path.Base(objectName)).Headers)
+
+	// 多个分片并发上传时，每个协程各自缓冲一个分片
+	if uploadOpts.PartJobs > 1 {
+		opts.NumThreads = uploadOpts.PartJobs
+		opts.ConcurrentStreamParts = true
+	}
+	return opts
+}
+
+// CatObject 将对象内容写入 w，不输出任何进度信息
+func (c *Client) CatObject(bucketName, objectName string, w io.Writer) error {
+	if objectName == "" || strings.HasSuffix(objectName, "/") {
+		return fmt.Errorf("%s/%s 不是一个对象", bucketName, objectName)
+	}
+
+	object, err := c.client.GetObject(c.ctx, bucketName, objectName, minio.GetObjectOptions{})
+	if err != nil {
+		return fmt.Errorf("获取对象失败: %w", err)
+	}
+	defer object.Close()
+
+	if _, err := io.Copy(w, object); err != nil {
+		return fmt.Errorf("读取对象 %s/%s 失败: %w", bucketName, objectName, err)
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package s3client
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// SymlinkMode 上传目录时对符号链接的处理方式
+type SymlinkMode string
+
+const (
+	SymlinkFollow   SymlinkMode = "follow"   // 上传链接指向的文件，进入链接指向的目录，跳过形成循环的链接
+	SymlinkSkip     SymlinkMode = "skip"     // 跳过所有符号链接
+	SymlinkPreserve SymlinkMode = "preserve" // 以零字节对象保存链接本身，目标路径记录在元数据中
+)
+
+// symlinkTargetHeader 保存符号链接目标路径的元数据
+const symlinkTargetHeader = "X-Amz-Meta-Symlink-Target"
+
+// ParseSymlinkMode 解析符号链接的处理方式，空字符串表示 follow
+func ParseSymlinkMode(s string) (SymlinkMode, error) {
+	switch mode := SymlinkMode(s); mode {
+	case "":
+		return SymlinkFollow, nil
+	case SymlinkFollow, SymlinkSkip, SymlinkPreserve:
+		return mode, nil
+	}
+	return "", fmt.Errorf("无效的符号链接处理方式 %q，可选值为 follow、skip 和 preserve", s)
+}
+
+// uploadWalker 遍历需要上传的目录，按 Symlinks 处理符号链接
+type uploadWalker struct {
+	root    string
+	opts    UploadOptions
+	ignore  *ignoreMatcher
+	skipped skipCount
+	listed  int // Only 中匹配到文件的路径数
+	fn      func(path string, info os.FileInfo) error
+	parents []os.FileInfo // 当前路径上的各级目录，用于发现指向上级目录的链接
+}
+
+// walk 处理 path，info 为 path 本身的信息，不跟随符号链接
+func (w *uploadWalker) walk(path string, info os.FileInfo) error {
+	rel, err := filepath.Rel(w.root, path)
+	if err != nil {
+		return fmt.Errorf("计算相对路径失败: %w", err)
+	}
+	rel = filepath.ToSlash(rel)
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		switch w.opts.Symlinks {
+		case SymlinkSkip:
+			w.skipped.links++
+			return nil
+		case SymlinkPreserve:
+			return w.file(path, rel, info)
+		}
+
+		// 目标不存在时按文件处理，由上传报告失败
+		target, err := os.Stat(path)
+		if err != nil {
+			return w.file(path, rel, info)
+		}
+		info = target
+	}
+
+	if info.IsDir() {
+		return w.dir(path, rel, info)
+	}
+	return w.file(path, rel, info)
+}
+
+// dir 按忽略规则处理目录，再依次处理其中的文件和子目录
+func (w *uploadWalker) dir(path, rel string, info os.FileInfo) error {
+	for _, parent := range w.parents {
+		if os.SameFile(parent, info) {
+			fmt.Printf("跳过符号链接 %s: 指向上级目录，形成循环\n", path)
+			w.skipped.links++
+			return nil
+		}
+	}
+
+	if w.ignore != nil {
+		if rel != "." && w.ignore.ignored(rel, true) {
+			w.skipped.dirs++
+			return nil
+		}
+		if rel == "." {
+			rel = ""
+		}
+		if err := w.ignore.load(path, rel); err != nil {
+			return err
+		}
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return err
+	}
+
+	w.parents = append(w.parents, info)
+	defer func() { w.parents = w.parents[:len(w.parents)-1] }()
+
+	for _, entry := range entries {
+		childInfo, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if err := w.walk(filepath.Join(path, entry.Name()), childInfo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// file 按忽略规则、Filter 和 Only 筛选文件
+func (w *uploadWalker) file(path, rel string, info os.FileInfo) error {
+	if w.ignore != nil && (filepath.Base(path) == IgnoreFileName || w.ignore.ignored(rel, false)) {
+		w.skipped.files++
+		return nil
+	}
+	if !w.opts.Filter.Match(rel) {
+		w.skipped.files++
+		return nil
+	}
+	if w.opts.Only != nil {
+		if !w.opts.Only[rel] {
+			return nil
+		}
+		w.listed++
+	}
+	return w.fn(path, info)
+}
+
+// uploadSymlink 以零字节对象保存符号链接，目标路径记录在元数据中
+func (c *Client) uploadSymlink(bucketName, objectName, target string, uploadOpts UploadOptions, newProgress progressFunc) error {
+	progress := newProgress(0)
+	opts := minio.PutObjectOptions{
+		UserMetadata: map[string]string{symlinkTargetHeader: target},
+		Progress:     progress,
+	}
+	if uploadOpts.IsPublic {
+		opts.UserMetadata["x-amz-acl"] = "public-read"
+	}
+
+	if _, err := c.client.PutObject(c.ctx, bucketName, objectName, strings.NewReader(""), 0, opts); err != nil {
+		return fmt.Errorf("上传符号链接失败: %w", err)
+	}
+	return nil
+}
+
+// symlinkTarget 返回对象保存的符号链接目标路径，不是符号链接时返回空字符串
+func symlinkTarget(objInfo minio.ObjectInfo) string {
+	if objInfo.Size != 0 {
+		return ""
+	}
+	return objInfo.Metadata.Get(symlinkTargetHeader)
+}
+
+// createSymlink 在 filePath 创建指向 target 的符号链接，替换已存在的文件或链接
+func createSymlink(filePath, target string) error {
+	if info, err := os.Lstat(filePath); err == nil {
+		if info.IsDir() {
+			return fmt.Errorf("%s 是一个目录，无法创建符号链接", filePath)
+		}
+		if err := os.Remove(filePath); err != nil {
+			return fmt.Errorf("删除已存在的文件失败: %w", err)
+		}
+	}
+	if err := os.Symlink(target, filePath); err != nil {
+		return fmt.Errorf("创建符号链接失败: %w", err)
+	}
+	return nil
+}
+
+// checkSymlinkParents 检查 filePath 在 dirPath 之下的各级目录中是否有符号链接，
+// 避免下载时经由之前恢复的符号链接写到目录之外
+func checkSymlinkParents(dirPath, filePath string) error {
+	rel, err := filepath.Rel(dirPath, filepath.Dir(filePath))
+	if err != nil || rel == "." {
+		return err
+	}
+
+	path := dirPath
+	for _, name := range strings.Split(rel, string(filepath.Separator)) {
+		path = filepath.Join(path, name)
+		info, err := os.Lstat(path)
+		if err != nil {
+			// 目录还不存在，之后的各级也不会是符号链接
+			return nil
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("%s 的上级目录 %s 是符号链接，拒绝写入", filePath, path)
+		}
+	}
+	return nil
+}
+
+// linkChanged 判断本地符号链接与远程对象是否不同，远程对象保存的是指向相同目标的符号链接时视为相同
+func (c *Client) linkChanged(bucketName, path string, object minio.ObjectInfo) (bool, error) {
+	if object.Size != 0 {
+		return true, nil
+	}
+	objInfo, err := c.client.StatObject(c.ctx, bucketName, object.Key, minio.StatObjectOptions{})
+	if err != nil {
+		return false, fmt.Errorf("获取对象信息失败: %w", err)
+	}
+	target, err := os.Readlink(path)
+	if err != nil {
+		return false, fmt.Errorf("读取符号链接失败: %w", err)
+	}
+	return symlinkTarget(objInfo) != target, nil
+}
+
+// pendingLink 等待创建的符号链接
+type pendingLink struct {
+	key    string // 对象名称
+	path   string
+	target string
+}
+
+// create 检查上级目录后创建符号链接
+func (l pendingLink) create(dirPath string) error {
+	if err := checkSymlinkParents(dirPath, l.path); err != nil {
+		return err
+	}
+	return createSymlink(l.path, l.target)
+}
+
+// linkQueue 并发下载目录时收集的符号链接
+type linkQueue struct {
+	mu    sync.Mutex
+	links []pendingLink
+}
+
+func (q *linkQueue) add(link pendingLink) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.links = append(q.links, link)
+}
+
+// sorted 按本地路径排序返回收集的符号链接
+func (q *linkQueue) sorted() []pendingLink {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return slices.SortedFunc(slices.Values(q.links), func(a, b pendingLink) int {
+		return strings.Compare(a.path, b.path)
+	})
+}
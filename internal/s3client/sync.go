@@ -0,0 +1,307 @@
+package s3client
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// SyncOptions 同步选项
+type SyncOptions struct {
+	Checksum bool            // 按 MD5 与 ETag 判断内容是否变化，默认按大小和修改时间判断
+	Delete   bool            // 删除目标端多余的文件或对象
+	Upload   UploadOptions   // 上传变化文件时使用的选项
+	Download DownloadOptions // 下载变化对象时使用的选项
+}
+
+// SyncResult 同步操作的统计结果
+type SyncResult struct {
+	Uploaded   int
+	Downloaded int
+	Skipped    int
+	Deleted    int
+}
+
+// SyncToRemote 将本地目录同步到前缀，只上传新增或变化的文件。
+// 设置 Delete 时删除本地已不存在的远程对象
+func (c *Client) SyncToRemote(dirPath, bucketName, prefix string, syncOpts SyncOptions) (SyncResult, error) {
+	var result SyncResult
+
+	info, err := os.Stat(dirPath)
+	if err != nil {
+		return result, fmt.Errorf("获取目录信息失败: %w", err)
+	}
+	if !info.IsDir() {
+		return result, fmt.Errorf("%s 不是一个目录", dirPath)
+	}
+
+	remote, err := c.listRemote(bucketName, prefix)
+	if err != nil {
+		return result, err
+	}
+
+	err = filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		if err := c.ctx.Err(); err != nil {
+			return err
+		}
+
+		objectName, err := objectNameFor(dirPath, path, prefix)
+		if err != nil {
+			return err
+		}
+
+		object, exists := remote[objectName]
+		delete(remote, objectName)
+		if exists {
+			changed, err := localChanged(path, info, object, syncOpts.Checksum)
+			if err != nil {
+				return err
+			}
+			if !changed {
+				result.Skipped++
+				return nil
+			}
+		}
+
+		if err := c.UploadFile(bucketName, path, objectName, syncOpts.Upload); err != nil {
+			return fmt.Errorf("上传文件 %s 失败: %w", path, err)
+		}
+		result.Uploaded++
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	if !syncOpts.Delete {
+		return result, nil
+	}
+
+	// 剩余的远程对象在本地已不存在
+	for objectName := range remote {
+		if err := c.ctx.Err(); err != nil {
+			return result, err
+		}
+		if c.dryRun {
+			planAction(dryRunDelete, bucketName+"/"+objectName, remote[objectName].Size)
+			result.Deleted++
+			continue
+		}
+		fmt.Printf("删除 %s/%s\n", bucketName, objectName)
+		if err := c.removeObject(bucketName, objectName); err != nil {
+			return result, fmt.Errorf("删除对象 %s 失败: %w", objectName, err)
+		}
+		result.Deleted++
+	}
+	return result, nil
+}
+
+// SyncFromRemote 将前缀同步到本地目录，只下载本地缺失或不同的对象，并将本地文件的
+// 修改时间设置为对象的最后修改时间，使下次同步可以按修改时间跳过未变化的对象。
+// 设置 Delete 时删除远程已不存在的本地文件
+func (c *Client) SyncFromRemote(bucketName, prefix, dirPath string, syncOpts SyncOptions) (SyncResult, error) {
+	var result SyncResult
+
+	// 目标目录可以不存在，下载时会自动创建
+	if info, err := os.Stat(dirPath); err == nil && !info.IsDir() {
+		return result, fmt.Errorf("%s 不是一个目录", dirPath)
+	}
+
+	prefix = dirPrefix(prefix)
+	remote, err := c.listRemote(bucketName, prefix)
+	if err != nil {
+		return result, err
+	}
+
+	// 下载时检查本地路径的各级目录，不经由之前恢复的符号链接写到目录之外
+	downloadOpts := syncOpts.Download
+	downloadOpts.root = dirPath
+
+	// 按对象名称排序，输出顺序稳定
+	keys := slices.Sorted(maps.Keys(remote))
+	wanted := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if err := c.ctx.Err(); err != nil {
+			return result, err
+		}
+
+		object := remote[key]
+		localPath, err := localPathFor(dirPath, prefix, key)
+		if err != nil {
+			return result, err
+		}
+		wanted[localPath] = true
+
+		// 不经由之前恢复的符号链接读写目录之外的文件，链接本身按目标路径比较
+		if err := checkSymlinkParents(dirPath, localPath); err != nil {
+			return result, fmt.Errorf("下载文件 %s 失败: %w", key, err)
+		}
+		info, err := os.Lstat(localPath)
+		switch {
+		case err == nil:
+			if info.IsDir() {
+				return result, fmt.Errorf("%s 是一个目录，无法写入对象 %s", localPath, key)
+			}
+			var changed bool
+			if info.Mode()&os.ModeSymlink != 0 {
+				changed, err = c.linkChanged(bucketName, localPath, object)
+			} else {
+				changed, err = remoteChanged(localPath, info, object, syncOpts.Checksum)
+			}
+			if err != nil {
+				return result, err
+			}
+			if !changed {
+				result.Skipped++
+				continue
+			}
+		case !errors.Is(err, os.ErrNotExist):
+			return result, fmt.Errorf("获取文件信息失败: %w", err)
+		}
+
+		if err := c.DownloadFile(bucketName, key, localPath, downloadOpts); err != nil {
+			return result, fmt.Errorf("下载文件 %s 失败: %w", key, err)
+		}
+		if c.dryRun {
+			result.Downloaded++
+			continue
+		}
+		// 符号链接不设置修改时间，否则会修改链接指向的文件
+		if info, err := os.Lstat(localPath); err == nil && info.Mode()&os.ModeSymlink != 0 {
+			result.Downloaded++
+			continue
+		}
+		if err := os.Chtimes(localPath, object.LastModified, object.LastModified); err != nil {
+			return result, fmt.Errorf("设置修改时间失败: %w", err)
+		}
+		result.Downloaded++
+	}
+
+	if !syncOpts.Delete {
+		return result, nil
+	}
+
+	// 删除远程已不存在的本地文件
+	err = filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			// 目标目录不存在时没有需要删除的文件
+			if path == dirPath && errors.Is(err, os.ErrNotExist) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if info.IsDir() || wanted[path] {
+			return nil
+		}
+		if err := c.ctx.Err(); err != nil {
+			return err
+		}
+
+		if c.dryRun {
+			planAction(dryRunDelete, path, info.Size())
+			result.Deleted++
+			return nil
+		}
+		fmt.Printf("删除 %s\n", path)
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("删除文件 %s 失败: %w", path, err)
+		}
+		result.Deleted++
+		return nil
+	})
+	return result, err
+}
+
+// listRemote 列出前缀下的所有对象，以对象名称为键，忽略目录标记
+func (c *Client) listRemote(bucketName, prefix string) (map[string]minio.ObjectInfo, error) {
+	objects := map[string]minio.ObjectInfo{}
+	for object := range c.ListObjects(bucketName, dirPrefix(prefix), true, false) {
+		if object.Err != nil {
+			return nil, fmt.Errorf("列出对象失败: %w", object.Err)
+		}
+		if strings.HasSuffix(object.Key, "/") {
+			continue
+		}
+		objects[object.Key] = object
+	}
+	return objects, nil
+}
+
+// dirPrefix 为非空前缀补上结尾的 /，只匹配前缀目录之内的对象，避免 p 匹配到 px/ 下的对象
+func dirPrefix(prefix string) string {
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		return prefix + "/"
+	}
+	return prefix
+}
+
+// localChanged 判断本地文件是否比远程对象新，用于上传方向
+func localChanged(path string, info os.FileInfo, object minio.ObjectInfo, checksum bool) (bool, error) {
+	return fileChanged(path, info, object, checksum, newerThan)
+}
+
+// remoteChanged 判断远程对象与本地文件是否不同，用于下载方向。下载后本地修改时间
+// 已设置为对象的最后修改时间，两者不相等即视为已变化
+func remoteChanged(path string, info os.FileInfo, object minio.ObjectInfo, checksum bool) (bool, error) {
+	return fileChanged(path, info, object, checksum, differsFrom)
+}
+
+// fileChanged 比较本地文件与远程对象。checksum 为 true 时比较文件 MD5 与对象 ETag；
+// 分片上传生成的 ETag 不是内容的 MD5，此时退回按大小和修改时间判断
+func fileChanged(path string, info os.FileInfo, object minio.ObjectInfo, checksum bool, modTimeChanged func(modTime, lastModified time.Time) bool) (bool, error) {
+	if info.Size() != object.Size {
+		return true, nil
+	}
+
+	if checksum && !strings.Contains(object.ETag, "-") {
+		sum, err := fileMD5(path)
+		if err != nil {
+			return false, err
+		}
+		return sum != trimETag(object.ETag), nil
+	}
+
+	return modTimeChanged(info.ModTime(), object.LastModified), nil
+}
+
+// newerThan 判断本地修改时间是否晚于对象的最后修改时间。
+// 不同服务返回的对象时间精度不同，统一截断到秒后比较
+func newerThan(modTime, lastModified time.Time) bool {
+	return modTime.Truncate(time.Second).After(lastModified.Truncate(time.Second))
+}
+
+// differsFrom 判断本地修改时间与对象的最后修改时间是否不同，精确到秒
+func differsFrom(modTime, lastModified time.Time) bool {
+	return !modTime.Truncate(time.Second).Equal(lastModified.Truncate(time.Second))
+}
+
+// fileMD5 计算文件内容的 MD5
+func fileMD5(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("打开文件失败: %w", err)
+	}
+	defer file.Close()
+
+	hash := md5.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("计算文件 MD5 失败: %w", err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,98 @@
+package s3client
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// TransferObject 将对象从当前客户端流式传输到另一个客户端（可以是不同的服务），
+// 数据直接从 GetObject 写入 PutObject，不落地到本地磁盘
+func (c *Client) TransferObject(dst *Client, srcBucket, srcObject, dstBucket, dstObject string, copyOpts CopyOptions) error {
+	if c.dryRun {
+		_, err := c.planCopy(dst, srcBucket, srcObject, dstBucket, dstObject)
+		return err
+	}
+	fmt.Printf("传输 %s/%s 到 %s/%s...\n", srcBucket, srcObject, dstBucket, dstObject)
+	_, err := c.transferObject(dst, srcBucket, srcObject, dstBucket, dstObject, copyOpts)
+	return err
+}
+
+// transferObject 将对象流式传输到另一个客户端，返回源对象的信息，暂时性的错误按重试策略重试
+func (c *Client) transferObject(dst *Client, srcBucket, srcObject, dstBucket, dstObject string, copyOpts CopyOptions) (minio.ObjectInfo, error) {
+	var info minio.ObjectInfo
+	err := c.withRetry(srcBucket+"/"+srcObject, c.fileProgress(srcBucket+"/"+srcObject), func(newProgress progressFunc) error {
+		var err error
+		info, err = c.transferObjectOnce(dst, srcBucket, srcObject, dstBucket, dstObject, copyOpts, newProgress)
+		return err
+	})
+	return info, err
+}
+
+// transferObjectOnce 执行一次流式传输
+func (c *Client) transferObjectOnce(dst *Client, srcBucket, srcObject, dstBucket, dstObject string, copyOpts CopyOptions, newProgress progressFunc) (minio.ObjectInfo, error) {
+	object, err := c.client.GetObject(c.ctx, srcBucket, srcObject, minio.GetObjectOptions{})
+	if err != nil {
+		return minio.ObjectInfo{}, fmt.Errorf("获取源对象失败: %w", err)
+	}
+	defer object.Close()
+
+	info, err := object.Stat()
+	if err != nil {
+		return info, fmt.Errorf("获取源对象信息失败: %w", err)
+	}
+
+	opts := putOptionsFromObject(info, copyOpts)
+	opts.Progress = c.throttled(newProgress(info.Size))
+
+	if _, err := dst.client.PutObject(dst.ctx, dstBucket, dstObject, object, info.Size, opts); err != nil {
+		return info, fmt.Errorf("写入目标对象失败: %w", err)
+	}
+	return info, nil
+}
+
+// TransferDirectory 将前缀下的所有对象流式传输到另一个客户端，保持相对路径不变
+func (c *Client) TransferDirectory(dst *Client, srcBucket, srcPrefix, dstBucket, dstPrefix string, copyOpts CopyOptions) error {
+	objects := c.ListObjects(srcBucket, srcPrefix, true, false)
+	for object := range objects {
+		if object.Err != nil {
+			return fmt.Errorf("列出对象失败: %w", object.Err)
+		}
+
+		// 跳过目录标记
+		if strings.HasSuffix(object.Key, "/") {
+			continue
+		}
+
+		dstObject := joinObjectPath(dstPrefix, strings.TrimPrefix(object.Key, srcPrefix))
+		if err := c.TransferObject(dst, srcBucket, object.Key, dstBucket, dstObject, copyOpts); err != nil {
+			return fmt.Errorf("传输对象 %s 失败: %w", object.Key, err)
+		}
+	}
+	return nil
+}
+
+// putOptionsFromObject 根据源对象的信息构建上传选项，保留用户元数据和标准 HTTP 头
+func putOptionsFromObject(info minio.ObjectInfo, copyOpts CopyOptions) minio.PutObjectOptions {
+	metadata := maps.Clone(info.UserMetadata)
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	maps.Copy(metadata, copyOpts.Metadata)
+
+	opts := minio.PutObjectOptions{
+		ContentType:        info.ContentType,
+		UserMetadata:       metadata,
+		ContentEncoding:    info.Metadata.Get("Content-Encoding"),
+		ContentDisposition: info.Metadata.Get("Content-Disposition"),
+		ContentLanguage:    info.Metadata.Get("Content-Language"),
+		CacheControl:       info.Metadata.Get("Cache-Control"),
+		Expires:            info.Expires,
+	}
+	if copyOpts.ContentType != "" {
+		opts.ContentType = copyOpts.ContentType
+	}
+	return opts
+}
@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseKeyValues 解析 key=value 格式的参数列表，值中可以包含 =
+func ParseKeyValues(values []string) (map[string]string, error) {
+	result := make(map[string]string, len(values))
+	for _, value := range values {
+		key, val, ok := strings.Cut(value, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("无效的参数 %q，格式应为 key=value", value)
+		}
+		result[key] = val
+	}
+	return result, nil
+}
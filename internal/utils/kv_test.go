@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKeyValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []string
+		expected map[string]string
+		wantErr  bool
+	}{
+		{
+			name:     "empty",
+			values:   nil,
+			expected: map[string]string{},
+		},
+		{
+			name:     "multiple pairs",
+			values:   []string{"owner=ops", "env=prod"},
+			expected: map[string]string{"owner": "ops", "env": "prod"},
+		},
+		{
+			name:     "value contains equals sign",
+			values:   []string{"query=a=b"},
+			expected: map[string]string{"query": "a=b"},
+		},
+		{
+			name:     "empty value",
+			values:   []string{"flag="},
+			expected: map[string]string{"flag": ""},
+		},
+		{
+			name:    "missing equals sign",
+			values:  []string{"owner"},
+			wantErr: true,
+		},
+		{
+			name:    "empty key",
+			values:  []string{"=ops"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseKeyValues(tt.values)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
@@ -31,6 +31,29 @@ func ParseS3Path(s3Path string) (bucket, object string, err error) {
 	return bucket, object, nil
 }
 
+// ParseProfileS3Path 解析可带配置名前缀的 S3 路径，例如 minio:s3://bucket/object，
+// 未指定配置名时 profile 为空
+func ParseProfileS3Path(s3Path string) (profile, bucket, object string, err error) {
+	profile, rest := SplitProfile(s3Path)
+	bucket, object, err = ParseS3Path(rest)
+	return profile, bucket, object, err
+}
+
+// SplitProfile 拆分路径中的配置名前缀，返回配置名和剩余路径
+func SplitProfile(s3Path string) (profile, rest string) {
+	idx := strings.Index(s3Path, ":s3://")
+	if idx <= 0 || strings.ContainsAny(s3Path[:idx], "/\\") {
+		return "", s3Path
+	}
+	return s3Path[:idx], s3Path[idx+1:]
+}
+
+// IsS3Path 判断路径是否为 S3 路径（可带配置名前缀）
+func IsS3Path(path string) bool {
+	_, rest := SplitProfile(path)
+	return strings.HasPrefix(rest, "s3://")
+}
+
 // ParseS3BucketPath 解析存储桶路径，确保不包含对象路径
 func ParseS3BucketPath(s3Path string) (bucket string, err error) {
 	if !strings.HasPrefix(s3Path, "s3://") {
@@ -188,3 +188,66 @@ func TestValidateBucketName(t *testing.T) {
 		})
 	}
 }
+
+func TestParseProfileS3Path(t *testing.T) {
+	tests := []struct {
+		name            string
+		path            string
+		expectedProfile string
+		expectedBucket  string
+		expectedObject  string
+		wantErr         bool
+	}{
+		{
+			name:           "without profile",
+			path:           "s3://mybucket/path/file.txt",
+			expectedBucket: "mybucket",
+			expectedObject: "path/file.txt",
+		},
+		{
+			name:            "with profile",
+			path:            "minio:s3://mybucket/path/file.txt",
+			expectedProfile: "minio",
+			expectedBucket:  "mybucket",
+			expectedObject:  "path/file.txt",
+		},
+		{
+			name:            "profile with prefix only",
+			path:            "oss:s3://mybucket/",
+			expectedProfile: "oss",
+			expectedBucket:  "mybucket",
+		},
+		{
+			name:    "local path containing s3 scheme",
+			path:    "./dir:s3://mybucket/file",
+			wantErr: true,
+		},
+		{
+			name:    "empty profile",
+			path:    ":s3://mybucket/file",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile, bucket, object, err := ParseProfileS3Path(tt.path)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedProfile, profile)
+				assert.Equal(t, tt.expectedBucket, bucket)
+				assert.Equal(t, tt.expectedObject, object)
+			}
+		})
+	}
+}
+
+func TestIsS3Path(t *testing.T) {
+	assert.True(t, IsS3Path("s3://mybucket/file"))
+	assert.True(t, IsS3Path("minio:s3://mybucket/file"))
+	assert.False(t, IsS3Path("./local/file"))
+	assert.False(t, IsS3Path("-"))
+}
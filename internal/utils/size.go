@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// sizeUnits 支持的大小单位，二进制单位按 1024 计算，十进制单位按 1000 计算
+var sizeUnits = map[string]int64{
+	"":    1,
+	"B":   1,
+	"K":   1 << 10,
+	"KI":  1 << 10,
+	"KIB": 1 << 10,
+	"KB":  1000,
+	"M":   1 << 20,
+	"MI":  1 << 20,
+	"MIB": 1 << 20,
+	"MB":  1000 * 1000,
+	"G":   1 << 30,
+	"GI":  1 << 30,
+	"GIB": 1 << 30,
+	"GB":  1000 * 1000 * 1000,
+	"T":   1 << 40,
+	"TI":  1 << 40,
+	"TIB": 1 << 40,
+	"TB":  1000 * 1000 * 1000 * 1000,
+}
+
+// ParseSize 解析带单位的大小字符串，例如 64MiB、512K、1GB
+func ParseSize(s string) (int64, error) {
+	trimmed := strings.TrimSpace(s)
+	if trimmed == "" {
+		return 0, fmt.Errorf("大小不能为空")
+	}
+
+	// 拆分数字部分和单位部分
+	i := 0
+	for i < len(trimmed) && (trimmed[i] >= '0' && trimmed[i] <= '9' || trimmed[i] == '.') {
+		i++
+	}
+	number, unit := trimmed[:i], strings.ToUpper(strings.TrimSpace(trimmed[i:]))
+	if number == "" {
+		return 0, fmt.Errorf("无效的大小: %s", s)
+	}
+
+	multiplier, ok := sizeUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("无效的大小单位: %s", s)
+	}
+
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("无效的大小: %s", s)
+	}
+
+	return int64(value * float64(multiplier)), nil
+}
+
+// ParseRate 解析每秒字节数，例如 20MiB/s、512K，可以省略 /s 后缀
+func ParseRate(s string) (int64, error) {
+	trimmed := strings.TrimSpace(s)
+	if strings.HasSuffix(strings.ToLower(trimmed), "/s") {
+		trimmed = trimmed[:len(trimmed)-2]
+	}
+	return ParseSize(trimmed)
+}
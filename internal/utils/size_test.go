@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected int64
+		wantErr  bool
+	}{
+		{name: "plain bytes", input: "1024", expected: 1024},
+		{name: "bytes with unit", input: "512B", expected: 512},
+		{name: "short binary unit", input: "8M", expected: 8 << 20},
+		{name: "binary unit", input: "64MiB", expected: 64 << 20},
+		{name: "lower case unit", input: "1gib", expected: 1 << 30},
+		{name: "decimal unit", input: "5MB", expected: 5 * 1000 * 1000},
+		{name: "fractional value", input: "1.5KiB", expected: 1536},
+		{name: "space between value and unit", input: "16 MiB", expected: 16 << 20},
+		{name: "empty", input: "", wantErr: true},
+		{name: "unknown unit", input: "10XB", wantErr: true},
+		{name: "missing number", input: "MiB", wantErr: true},
+		{name: "invalid number", input: "1.2.3M", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseSize(tt.input)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected int64
+		wantErr  bool
+	}{
+		{name: "per second suffix", input: "20MiB/s", expected: 20 << 20},
+		{name: "upper case suffix", input: "512K/S", expected: 512 << 10},
+		{name: "without suffix", input: "1MB", expected: 1000 * 1000},
+		{name: "zero means unlimited", input: "0", expected: 0},
+		{name: "only suffix", input: "/s", wantErr: true},
+		{name: "unknown unit", input: "10XB/s", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseRate(tt.input)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}